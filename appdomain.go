@@ -5,7 +5,6 @@ package clr
 
 import (
 	"fmt"
-	"strings"
 	"syscall"
 	"unsafe"
 
@@ -154,7 +153,7 @@ func (obj *AppDomain) GetHashCode() (int32, error) {
 // GetFriendlyName returns the friendlyname of the appdomain
 func (obj *AppDomain) GetFriendlyName() (name string, err error) {
 	debugPrint("Entering into appdomain.GetFriendlyName()...")
-	var bstrFriendlyname unsafe.Pointer
+	var bstrFriendlyname BSTR
 	hr, _, err := syscall.SyscallN(
 		obj.vtbl.get_FriendlyName,
 		uintptr(unsafe.Pointer(obj)),
@@ -168,7 +167,8 @@ func (obj *AppDomain) GetFriendlyName() (name string, err error) {
 		err = fmt.Errorf("the appdomain.GetFriendlyName function returned a non-zero HRESULT: 0x%x", hr)
 		return
 	}
-	return ReadUnicodeStr(unsafe.Pointer(bstrFriendlyname)), nil
+	defer bstrFriendlyname.Free()
+	return bstrFriendlyname.String(), nil
 }
 
 // Load_3 Loads an Assembly into this application domain.
@@ -200,11 +200,146 @@ func (obj *AppDomain) Load_3(rawAssembly *SafeArray) (assembly *Assembly, err er
 	return
 }
 
+// CreateInstance creates an instance of the type typeName, such as "Namespace.Class", defined in the assembly with the
+// display name assemblyName, calling the public constructor that matches the types of the arguments. The instance is
+// returned in an ObjectHandle that must be unwrapped and released by the caller
+//
+//	virtual HRESULT __stdcall CreateInstance_3 (
+//	/*[in]*/ BSTR AssemblyName,
+//	/*[in]*/ BSTR typeName,
+//	/*[in]*/ VARIANT_BOOL ignoreCase,
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[in]*/ struct _Binder * Binder,
+//	/*[in]*/ SAFEARRAY * args,
+//	/*[in]*/ struct _CultureInfo * culture,
+//	/*[in]*/ SAFEARRAY * activationAttributes,
+//	/*[in]*/ struct _Evidence * securityAttributes,
+//	/*[out,retval]*/ struct _ObjectHandle * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.createinstance?view=netframework-4.8
+func (obj *AppDomain) CreateInstance(assemblyName, typeName string, args ...any) (*ObjectHandle, error) {
+	debugPrint("Entering into appdomain.CreateInstance()...")
+	return obj.createInstance(obj.vtbl.CreateInstance_3, "CreateInstance_3", assemblyName, typeName, args)
+}
+
+// CreateInstanceFrom creates an instance of the type typeName defined in the assembly file at the path assemblyFile,
+// as CreateInstance does
+//
+//	virtual HRESULT __stdcall CreateInstanceFrom_3 (
+//	/*[in]*/ BSTR assemblyFile,
+//	/*[in]*/ BSTR typeName,
+//	/*[in]*/ VARIANT_BOOL ignoreCase,
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[in]*/ struct _Binder * Binder,
+//	/*[in]*/ SAFEARRAY * args,
+//	/*[in]*/ struct _CultureInfo * culture,
+//	/*[in]*/ SAFEARRAY * activationAttributes,
+//	/*[in]*/ struct _Evidence * securityAttributes,
+//	/*[out,retval]*/ struct _ObjectHandle * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.createinstancefrom?view=netframework-4.8
+func (obj *AppDomain) CreateInstanceFrom(assemblyFile, typeName string, args ...any) (*ObjectHandle, error) {
+	debugPrint("Entering into appdomain.CreateInstanceFrom()...")
+	return obj.createInstance(obj.vtbl.CreateInstanceFrom_3, "CreateInstanceFrom_3", assemblyFile, typeName, args)
+}
+
+// createInstance calls CreateInstance_3 or CreateInstanceFrom_3, which take the same parameters
+func (obj *AppDomain) createInstance(method uintptr, name, assembly, typeName string, args []any) (handle *ObjectHandle, err error) {
+	bstrAssembly, err := SysAllocString(assembly)
+	if err != nil {
+		return
+	}
+	defer bstrAssembly.Free()
+	bstrTypeName, err := SysAllocString(typeName)
+	if err != nil {
+		return
+	}
+	defer bstrTypeName.Free()
+
+	parameters, err := SafeArrayFromSlice(args)
+	if err != nil {
+		return
+	}
+	defer parameters.Destroy()
+
+	hr, _, _ := syscall.SyscallN(
+		method,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrAssembly.Pointer()),
+		uintptr(bstrTypeName.Pointer()),
+		uintptr(uint16(variantBool(false))),
+		uintptr(instanceMembers|BindingFlags_CreateInstance),
+		0,
+		uintptr(unsafe.Pointer(parameters.SafeArray())),
+		0,
+		0,
+		0,
+		uintptr(unsafe.Pointer(&handle)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the AppDomain::%s method returned a non-zero HRESULT: 0x%x", name, hr)
+		return
+	}
+	if handle == nil {
+		err = fmt.Errorf("the %s type was not found in %s", typeName, assembly)
+	}
+	return
+}
+
+// ExecuteAssembly runs the managed executable at the path assemblyFile in the application domain, calling its entry
+// point with the arguments like the CLR does when the EXE is started, and returns the value returned by Main, 0 when it
+// returns void. A missing file or dependency returns a *FileNotFoundError and a file that is not a valid assembly a
+// *BadImageFormatError. The output of the executable is not captured, use RedirectStdoutStderr for that
+//
+//	virtual HRESULT __stdcall ExecuteAssembly_3 (
+//	/*[in]*/ BSTR assemblyFile,
+//	/*[in]*/ struct _Evidence * assemblySecurity,
+//	/*[in]*/ SAFEARRAY * args,
+//	/*[out,retval]*/ long * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.executeassembly?view=netframework-4.8#System_AppDomain_ExecuteAssembly_System_String_System_Security_Policy_Evidence_System_String___
+func (obj *AppDomain) ExecuteAssembly(assemblyFile string, args ...string) (exitCode int32, err error) {
+	debugPrint("Entering into appdomain.ExecuteAssembly()...")
+	exitCode = -1
+	bstrAssemblyFile, err := SysAllocString(assemblyFile)
+	if err != nil {
+		return
+	}
+	defer bstrAssemblyFile.Free()
+
+	// Main always gets an array, empty when there are no arguments
+	if args == nil {
+		args = []string{}
+	}
+	arguments, err := SafeArrayFromSlice(args)
+	if err != nil {
+		return
+	}
+	defer arguments.Destroy()
+
+	var ret int32
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.ExecuteAssembly_3,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrAssemblyFile.Pointer()),
+		0,
+		uintptr(unsafe.Pointer(arguments.SafeArray())),
+		uintptr(unsafe.Pointer(&ret)),
+	)
+	if hr != S_OK {
+		if err = assemblyLoadError(uint32(hr), assemblyFile); err == nil {
+			err = fmt.Errorf("the AppDomain::ExecuteAssembly_3 method returned a non-zero HRESULT: 0x%x", hr)
+		}
+		return
+	}
+	return ret, nil
+}
+
 // ToString Obtains a string representation that includes the friendly name of the application domain and any context policies.
 // https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.tostring?view=net-5.0#System_AppDomain_ToString
 func (obj *AppDomain) ToString() (domain string, err error) {
 	debugPrint("Entering into appdomain.ToString()...")
-	var pDomain *string
+	var pDomain BSTR
 	hr, _, err := syscall.SyscallN(
 		obj.vtbl.get_ToString,
 		uintptr(unsafe.Pointer(obj)),
@@ -220,38 +355,82 @@ func (obj *AppDomain) ToString() (domain string, err error) {
 		return
 	}
 	err = nil
-	domain = ReadUnicodeStr(unsafe.Pointer(pDomain))
+	domain = pDomain.String()
+	pDomain.Free()
 	return
 }
 
-// Load_2 takes an assemblystring (name) and checks each Assembly in the appdomain for a prefix match (case insensitive). If a match is found, it is returned.
-func (obj *AppDomain) Load_2(assemblyString string) (*Assembly, error) {
-	// var err error
-	// var pAssembly *Assembly
-	// str, _ := SysAllocString(assemblyString)
-	// ret, _, _ := syscall.SyscallN(
-	// 	obj.vtbl.Load_2,
-	// 	uintptr(unsafe.Pointer(obj)),
-	// 	uintptr(unsafe.Pointer(str)),
-	// 	uintptr(unsafe.Pointer(&pAssembly)))
-	// if ret != 0 {
-	// 	err = fmt.Errorf("bad load 2: %x", ret)
-	// }
-	// return pAssembly, err
-	//load_2 isn't working nicely, and appears to want a fully qualified assembly name - so let's do the (dumb?) thing and use ListAssemblies to return one that has the right prefix
+// Load_2 loads the assembly with the specified display name into the application domain, such as
+// "System.Xml, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089". The CLR does not look for partial
+// names, those without a version, culture or public key token, in the global assembly cache, so if such a name fails
+// to load the assemblies already loaded into the domain are searched for the highest version that matches it with
+// AssemblyName.Matches
+//
+//	virtual HRESULT __stdcall Load_2 (
+//	/*[in]*/ BSTR assemblyString,
+//	/*[out,retval]*/ struct _Assembly * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.load?view=netframework-4.8#System_AppDomain_Load_System_String_
+func (obj *AppDomain) Load_2(assemblyString string) (assembly *Assembly, err error) {
 	debugPrint("Entering into appdomain.Load_2()...")
-	asmb, err := obj.ListAssemblies()
+	bstrAssembly, err := SysAllocString(assemblyString)
+	if err != nil {
+		return
+	}
+	defer bstrAssembly.Free()
+
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.Load_2,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrAssembly.Pointer()),
+		uintptr(unsafe.Pointer(&assembly)),
+	)
+	if hr == S_OK {
+		return
+	}
+	err = fmt.Errorf("the AppDomain::Load_2 method returned a non-zero HRESULT: 0x%x", hr)
+
+	name, errP := ParseAssemblyName(assemblyString)
+	if errP != nil || !name.IsPartial() {
+		return
+	}
+	if loaded, errF := obj.findLoadedAssembly(name); errF == nil && loaded != nil {
+		return loaded, nil
+	}
+	return
+}
+
+// Load loads the assembly with the specified name into the application domain, by formatting its display name for
+// Load_2
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.load?view=netframework-4.8#System_AppDomain_Load_System_Reflection_AssemblyName_
+func (obj *AppDomain) Load(name AssemblyName) (*Assembly, error) {
+	debugPrint("Entering into appdomain.Load()...")
+	return obj.Load_2(name.String())
+}
+
+// findLoadedAssembly returns the loaded assembly with the highest version that matches name, nil if there is none
+func (obj *AppDomain) findLoadedAssembly(name AssemblyName) (found *Assembly, err error) {
+	assemblies, err := obj.ListAssemblies()
 	if err != nil {
-		return nil, err
+		return
 	}
-	for i := range asmb {
-		if name, err := asmb[i].GetFullName(); err == nil {
-			if strings.HasPrefix(strings.ToLower(name), strings.ToLower(assemblyString)) {
-				return asmb[i], nil
+	var foundVersion *Version
+	for _, assembly := range assemblies {
+		candidate, errN := assembly.GetName()
+		if errN != nil || !name.Matches(*candidate) {
+			assembly.Release()
+			continue
+		}
+		if found == nil || (candidate.Version != nil && (foundVersion == nil || candidate.Version.Compare(*foundVersion) > 0)) {
+			if found != nil {
+				found.Release()
 			}
+			found, foundVersion = assembly, candidate.Version
+			continue
 		}
+		assembly.Release()
 	}
-	return nil, fmt.Errorf("could not find assembly with name %s", assemblyString)
+	return
 }
 
 func (obj *AppDomain) GetAssemblies() (safeArray *SafeArray, err error) {
@@ -278,34 +457,30 @@ func (obj *AppDomain) ListAssemblies() (assemblies []*Assembly, err error) {
 	if err != nil {
 		return
 	}
-	//get dimensions of array (should be 1 for this context always)
-	d, err := SafeArrayGetDim(safeArray)
-	if err != nil {
-		return
-	}
-	if d != 1 {
-		return nil, fmt.Errorf("expected dimension of 1, got %d", d)
-	}
+	return interfacesFromSafeArray[Assembly](safeArray)
+}
 
-	lbound, err := SafeArrayGetLBound(safeArray, d)
+// ListAssemblyInfo returns the name, location and references of every assembly loaded into the AppDomain. Sort the
+// inventory with sort.Sort to order it by name and version
+func (obj *AppDomain) ListAssemblyInfo() (inventory AssemblyInventory, err error) {
+	debugPrint("Entering into appdomain.ListAssemblyInfo()...")
+	assemblies, err := obj.ListAssemblies()
 	if err != nil {
 		return
 	}
+	defer func() {
+		for _, assembly := range assemblies {
+			assembly.Release()
+		}
+	}()
 
-	ubound, err := SafeArrayGetUBound(safeArray, d)
-	if err != nil {
-		return
-	}
-	arrlen := ubound - lbound
-	//avoids allocs (lol, overkill)
-	assemblies = make([]*Assembly, 0, arrlen)
-	for i := lbound; i <= ubound; i++ {
-		pApp, err := SafeArrayGetElement(safeArray, i)
+	inventory = make(AssemblyInventory, 0, len(assemblies))
+	for _, assembly := range assemblies {
+		info, err := assembly.Info()
 		if err != nil {
 			return nil, err
 		}
-		app := (*Assembly)(pApp)
-		assemblies = append(assemblies, app)
+		inventory = append(inventory, info)
 	}
-	return
+	return inventory, nil
 }
@@ -0,0 +1,189 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+	"io/fs"
+	"reflect"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+// SetData stores a value under the name in the application domain. Managed code running in the domain reads it with
+// AppDomain.CurrentDomain.GetData(name) and stores its own values with SetData for the host to read with GetData.
+// The value is converted with ToVariant: strings, numbers, []byte and []string become System.String, the matching
+// numeric type, byte[] and string[]. A nil value removes the data
+//
+//	virtual HRESULT __stdcall SetData (
+//	/*[in]*/ BSTR name,
+//	/*[in]*/ VARIANT data ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.setdata?view=netframework-4.8#System_AppDomain_SetData_System_String_System_Object_
+func (obj *AppDomain) SetData(name string, data any) error {
+	debugPrint("Entering into appdomain.SetData()...")
+	bstrName, err := SysAllocString(name)
+	if err != nil {
+		return err
+	}
+	defer bstrName.Free()
+
+	variantData, err := ToVariant(data)
+	if err != nil {
+		return err
+	}
+	// A Variant is copied as is and still belongs to the caller
+	if _, borrowed := data.(Variant); !borrowed {
+		defer variantData.Clear()
+	}
+
+	// The VARIANT is passed by value following the calling convention of the architecture
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	args := []uintptr{uintptr(unsafe.Pointer(obj)), uintptr(bstrName.Pointer())}
+	args = appendVariantArg(args, &variantData, &pinner)
+	hr, _, _ := syscall.SyscallN(obj.vtbl.SetData, args...)
+	if hr != S_OK {
+		return fmt.Errorf("the AppDomain::SetData method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return nil
+}
+
+// GetData returns the value stored under the name in the application domain, converted with FromVariant, or nil if
+// there is none. An *IUnknown value holds a reference that must be released by the caller
+//
+//	virtual HRESULT __stdcall GetData (
+//	/*[in]*/ BSTR name,
+//	/*[out,retval]*/ VARIANT * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.getdata?view=netframework-4.8
+func (obj *AppDomain) GetData(name string) (any, error) {
+	debugPrint("Entering into appdomain.GetData()...")
+	bstrName, err := SysAllocString(name)
+	if err != nil {
+		return nil, err
+	}
+	defer bstrName.Free()
+
+	var ret Variant
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetData,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrName.Pointer()),
+		uintptr(unsafe.Pointer(&ret)),
+	)
+	if hr != S_OK {
+		return nil, fmt.Errorf("the AppDomain::GetData method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return takeVariant(&ret)
+}
+
+// GetDataString returns the System.String stored under the name in the application domain. An error wrapping
+// fs.ErrNotExist is returned if there is no value
+func (obj *AppDomain) GetDataString(name string) (string, error) {
+	value, err := obj.getData(name)
+	if err != nil {
+		return "", err
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", dataTypeError(name, value, "string")
+	}
+	return s, nil
+}
+
+// GetDataBytes returns the byte[] stored under the name in the application domain. An error wrapping fs.ErrNotExist
+// is returned if there is no value
+func (obj *AppDomain) GetDataBytes(name string) ([]byte, error) {
+	value, err := obj.getData(name)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := value.([]byte)
+	if !ok {
+		return nil, dataTypeError(name, value, "byte[]")
+	}
+	return b, nil
+}
+
+// GetDataStrings returns the string[] stored under the name in the application domain, an object[] holding only
+// strings is accepted too. An error wrapping fs.ErrNotExist is returned if there is no value
+func (obj *AppDomain) GetDataStrings(name string) ([]string, error) {
+	value, err := obj.getData(name)
+	if err != nil {
+		return nil, err
+	}
+	switch value := value.(type) {
+	case []string:
+		return value, nil
+	case []any:
+		strs := make([]string, len(value))
+		for i, element := range value {
+			s, ok := element.(string)
+			if !ok {
+				releaseInterfaces(value)
+				return nil, fmt.Errorf("element %d of the %s data is a %T, not a string", i, name, element)
+			}
+			strs[i] = s
+		}
+		return strs, nil
+	}
+	return nil, dataTypeError(name, value, "string[]")
+}
+
+// GetDataInt64 returns the integer stored under the name in the application domain, whatever its managed integer
+// type. Floating point values are accepted if they are whole numbers. An error wrapping fs.ErrNotExist is returned if
+// there is no value
+func (obj *AppDomain) GetDataInt64(name string) (int64, error) {
+	value, err := obj.getNumber(name, reflect.TypeOf(int64(0)))
+	if err != nil {
+		return 0, err
+	}
+	return value.Int(), nil
+}
+
+// GetDataFloat64 returns the number stored under the name in the application domain, whatever its managed numeric
+// type. An error wrapping fs.ErrNotExist is returned if there is no value
+func (obj *AppDomain) GetDataFloat64(name string) (float64, error) {
+	value, err := obj.getNumber(name, reflect.TypeOf(float64(0)))
+	if err != nil {
+		return 0, err
+	}
+	return value.Float(), nil
+}
+
+// getData calls GetData and returns an error wrapping fs.ErrNotExist instead of a nil value
+func (obj *AppDomain) getData(name string) (any, error) {
+	value, err := obj.GetData(name)
+	if err != nil {
+		return nil, err
+	}
+	if value == nil {
+		return nil, fmt.Errorf("the %s data is not set in the application domain: %w", name, fs.ErrNotExist)
+	}
+	return value, nil
+}
+
+// getNumber calls getData and converts the number to goType without losing its value
+func (obj *AppDomain) getNumber(name string, goType reflect.Type) (reflect.Value, error) {
+	value, err := obj.getData(name)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	converted, err := convertNumber(reflect.ValueOf(value), goType)
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("the %s data: %w", name, err)
+	}
+	if !converted.IsValid() {
+		return reflect.Value{}, dataTypeError(name, value, "number")
+	}
+	return converted, nil
+}
+
+// dataTypeError releases an interface read by GetData and returns the error of a value of an unexpected type
+func dataTypeError(name string, value any, expected string) error {
+	releaseInterfaces(value)
+	return fmt.Errorf("the %s data is a %T, not a %s", name, value, expected)
+}
@@ -0,0 +1,336 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+// Subscription is a Go handler added to an event of an AppDomain. The handler is called on the managed thread that
+// raises the event until the subscription is closed
+type Subscription struct {
+	domain   *AppDomain
+	event    string
+	remove   uintptr
+	delegate *IUnknown
+	// closed is called once the handler is removed, to release what it holds
+	closed func()
+}
+
+// Close removes the handler from the event of the domain
+func (s *Subscription) Close() error {
+	if s.delegate == nil {
+		return fmt.Errorf("the %s subscription is already closed", s.event)
+	}
+	hr, _, _ := syscall.SyscallN(
+		s.remove,
+		uintptr(unsafe.Pointer(s.domain)),
+		uintptr(unsafe.Pointer(s.delegate)),
+	)
+	s.delegate.Release()
+	s.domain.Release()
+	if s.closed != nil {
+		s.closed()
+	}
+	s.delegate, s.domain, s.closed = nil, nil, nil
+	if hr != S_OK {
+		return fmt.Errorf("the AppDomain::remove_%s method returned a non-zero HRESULT: 0x%x", s.event, hr)
+	}
+	return nil
+}
+
+// subscribe adds a delegate of the mscorlib type delegateTypeName calling handler to an event of the domain with its
+// add method, the returned Subscription removes it with the remove method
+func (obj *AppDomain) subscribe(event, delegateTypeName string, add, remove uintptr, handler func(args []any) Variant) (*Subscription, error) {
+	domainType, err := (*IUnknown)(unsafe.Pointer(obj)).GetType()
+	if err != nil {
+		return nil, err
+	}
+	defer domainType.Release()
+	delegateType, err := mscorlibType(domainType, delegateTypeName)
+	if err != nil {
+		return nil, err
+	}
+	defer delegateType.Release()
+
+	delegate, err := newDelegate(obj, delegateType, handler)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error creating the %s handler:\n%s", event, err)
+	}
+	hr, _, _ := syscall.SyscallN(
+		add,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(delegate)),
+	)
+	if hr != S_OK {
+		delegate.Release()
+		return nil, fmt.Errorf("the AppDomain::add_%s method returned a non-zero HRESULT: 0x%x", event, hr)
+	}
+	obj.AddRef()
+	return &Subscription{domain: obj, event: event, remove: remove, delegate: delegate}, nil
+}
+
+// AddAssemblyResolve adds a handler to the AssemblyResolve event of the domain, raised when the CLR can't find an
+// assembly, that calls resolver with the requested name and loads the returned bytes with Load_3. Each resolved
+// assembly is loaded once and returned again for the same name, so that its types have a single identity. Combined
+// with MapResolver, FSResolver or DirResolver, it lets an assembly loaded from memory find its dependencies
+//
+//	virtual HRESULT __stdcall add_AssemblyResolve (
+//	/*[in]*/ struct _ResolveEventHandler * value ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.assemblyresolve?view=netframework-4.8
+func (obj *AppDomain) AddAssemblyResolve(resolver AssemblyResolver) (*Subscription, error) {
+	debugPrint("Entering into appdomain.AddAssemblyResolve()...")
+	var mu sync.Mutex
+	resolved := make(map[string]*Assembly)
+
+	handler := func(args []any) Variant {
+		e := eventArgs(args)
+		if e == nil {
+			return Variant{}
+		}
+		requested, err := e.getProperty("Name")
+		if err != nil {
+			debugPrint(fmt.Sprintf("there was an error reading the requested assembly name: %s", err))
+			return Variant{}
+		}
+		displayName, _ := requested.(string)
+		name, err := ParseAssemblyName(displayName)
+		if err != nil {
+			debugPrint(err.Error())
+			return Variant{}
+		}
+
+		// The lock isn't held while loading, which can raise the event again for the dependencies of the assembly
+		key := strings.ToLower(name.String())
+		mu.Lock()
+		assembly, ok := resolved[key]
+		mu.Unlock()
+		if !ok {
+			raw, found := resolver(name)
+			if !found {
+				return Variant{}
+			}
+			if assembly, err = obj.load(raw); err != nil {
+				debugPrint(fmt.Sprintf("there was an error loading the %s assembly: %s", displayName, err))
+				return Variant{}
+			}
+			mu.Lock()
+			if loaded, ok := resolved[key]; ok {
+				assembly.Release()
+				assembly = loaded
+			} else {
+				resolved[key] = assembly
+			}
+			mu.Unlock()
+		}
+		v, _ := ToVariant(assembly)
+		return v
+	}
+
+	subscription, err := obj.subscribe("AssemblyResolve", "System.ResolveEventHandler", obj.vtbl.add_AssemblyResolve, obj.vtbl.remove_AssemblyResolve, handler)
+	if err != nil {
+		return nil, err
+	}
+	subscription.closed = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for key, assembly := range resolved {
+			assembly.Release()
+			delete(resolved, key)
+		}
+	}
+	return subscription, nil
+}
+
+// OnUnhandledException adds a handler to the UnhandledException event of the domain, raised when an exception is
+// not caught by managed code, such as one thrown by a background thread. The CLR terminates the process once the
+// handlers of a terminating exception return, the handler is the last chance to log it
+//
+//	virtual HRESULT __stdcall add_UnhandledException (
+//	/*[in]*/ struct _UnhandledExceptionEventHandler * value ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.unhandledexception?view=netframework-4.8
+func (obj *AppDomain) OnUnhandledException(handler func(UnhandledExceptionEvent)) (*Subscription, error) {
+	debugPrint("Entering into appdomain.OnUnhandledException()...")
+	return obj.subscribe("UnhandledException", "System.UnhandledExceptionEventHandler", obj.vtbl.add_UnhandledException, obj.vtbl.remove_UnhandledException, func(args []any) Variant {
+		var event UnhandledExceptionEvent
+		if e := eventArgs(args); e != nil {
+			if exception, err := e.getProperty("ExceptionObject"); err == nil {
+				if unknown, ok := exception.(*IUnknown); ok && unknown != nil {
+					event.Exception = readException(unknown)
+				}
+				releaseInterfaces(exception)
+			}
+			isTerminating, _ := e.getProperty("IsTerminating")
+			event.IsTerminating, _ = isTerminating.(bool)
+		}
+		handler(event)
+		return Variant{}
+	})
+}
+
+// OnProcessExit adds a handler to the ProcessExit event of the domain, raised when the process exits
+//
+//	virtual HRESULT __stdcall add_ProcessExit (
+//	/*[in]*/ struct _EventHandler * value ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.processexit?view=netframework-4.8
+func (obj *AppDomain) OnProcessExit(handler func(DomainEvent)) (*Subscription, error) {
+	debugPrint("Entering into appdomain.OnProcessExit()...")
+	return obj.subscribe("ProcessExit", "System.EventHandler", obj.vtbl.add_ProcessExit, obj.vtbl.remove_ProcessExit, domainEventHandler(handler))
+}
+
+// OnDomainUnload adds a handler to the DomainUnload event of the domain, raised when the domain is about to be
+// unloaded. The default domain is never unloaded and doesn't raise it
+//
+//	virtual HRESULT __stdcall add_DomainUnload (
+//	/*[in]*/ struct _EventHandler * value ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.domainunload?view=netframework-4.8
+func (obj *AppDomain) OnDomainUnload(handler func(DomainEvent)) (*Subscription, error) {
+	debugPrint("Entering into appdomain.OnDomainUnload()...")
+	return obj.subscribe("DomainUnload", "System.EventHandler", obj.vtbl.add_DomainUnload, obj.vtbl.remove_DomainUnload, domainEventHandler(handler))
+}
+
+// OnAssemblyLoad adds a handler to the AssemblyLoad event of the domain, raised when an assembly is loaded
+//
+//	virtual HRESULT __stdcall add_AssemblyLoad (
+//	/*[in]*/ struct _AssemblyLoadEventHandler * value ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.assemblyload?view=netframework-4.8
+func (obj *AppDomain) OnAssemblyLoad(handler func(AssemblyLoadEvent)) (*Subscription, error) {
+	debugPrint("Entering into appdomain.OnAssemblyLoad()...")
+	return obj.subscribe("AssemblyLoad", "System.AssemblyLoadEventHandler", obj.vtbl.add_AssemblyLoad, obj.vtbl.remove_AssemblyLoad, func(args []any) Variant {
+		var event AssemblyLoadEvent
+		if e := eventArgs(args); e != nil {
+			if loaded, err := e.getProperty("LoadedAssembly"); err == nil {
+				if unknown, ok := loaded.(*IUnknown); ok && unknown != nil {
+					var assembly *Assembly
+					if unknown.QueryInterface(IID__Assembly, unsafe.Pointer(&assembly)) == nil {
+						if name, errN := assembly.GetName(); errN == nil {
+							event.Name = *name
+						}
+						event.FullName, _ = assembly.GetFullName()
+						// Dynamic assemblies have no location
+						event.Location, _ = assembly.GetLocation()
+						assembly.Release()
+					}
+				}
+				releaseInterfaces(loaded)
+			}
+		}
+		handler(event)
+		return Variant{}
+	})
+}
+
+// OnTypeResolve adds a handler to the TypeResolve event of the domain, raised when the CLR can't find a type. The
+// handler only observes the event, the type is still not found
+//
+//	virtual HRESULT __stdcall add_TypeResolve (
+//	/*[in]*/ struct _ResolveEventHandler * value ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.typeresolve?view=netframework-4.8
+func (obj *AppDomain) OnTypeResolve(handler func(ResolveEvent)) (*Subscription, error) {
+	debugPrint("Entering into appdomain.OnTypeResolve()...")
+	return obj.subscribe("TypeResolve", "System.ResolveEventHandler", obj.vtbl.add_TypeResolve, obj.vtbl.remove_TypeResolve, resolveEventHandler(handler))
+}
+
+// OnResourceResolve adds a handler to the ResourceResolve event of the domain, raised when the CLR can't find a
+// manifest resource. The handler only observes the event, the resource is still not found
+//
+//	virtual HRESULT __stdcall add_ResourceResolve (
+//	/*[in]*/ struct _ResolveEventHandler * value ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomain.resourceresolve?view=netframework-4.8
+func (obj *AppDomain) OnResourceResolve(handler func(ResolveEvent)) (*Subscription, error) {
+	debugPrint("Entering into appdomain.OnResourceResolve()...")
+	return obj.subscribe("ResourceResolve", "System.ResolveEventHandler", obj.vtbl.add_ResourceResolve, obj.vtbl.remove_ResourceResolve, resolveEventHandler(handler))
+}
+
+// domainEventHandler calls handler with the friendly name of the domain that sent an EventHandler event
+func domainEventHandler(handler func(DomainEvent)) func(args []any) Variant {
+	return func(args []any) Variant {
+		var event DomainEvent
+		if len(args) > 0 {
+			if sender, ok := args[0].(*IUnknown); ok && sender != nil {
+				if name, err := sender.getProperty("FriendlyName"); err == nil {
+					event.FriendlyName, _ = name.(string)
+				}
+			}
+		}
+		handler(event)
+		return Variant{}
+	}
+}
+
+// resolveEventHandler calls handler with the name requested by a ResolveEventHandler event and returns null
+func resolveEventHandler(handler func(ResolveEvent)) func(args []any) Variant {
+	return func(args []any) Variant {
+		var event ResolveEvent
+		if e := eventArgs(args); e != nil {
+			if name, err := e.getProperty("Name"); err == nil {
+				event.Name, _ = name.(string)
+			}
+			// RequestingAssembly is only available since the .NET Framework 4
+			if requesting, err := e.getProperty("RequestingAssembly"); err == nil {
+				if unknown, ok := requesting.(*IUnknown); ok && unknown != nil {
+					if fullName, errF := unknown.getProperty("FullName"); errF == nil {
+						event.RequestingAssembly, _ = fullName.(string)
+					}
+				}
+				releaseInterfaces(requesting)
+			}
+		}
+		handler(event)
+		return Variant{}
+	}
+}
+
+// eventArgs returns the EventArgs passed to an event handler with the sender, nil if there are none
+func eventArgs(args []any) *IUnknown {
+	if len(args) != 2 {
+		return nil
+	}
+	e, _ := args[1].(*IUnknown)
+	return e
+}
+
+// readException reads the type, message, stack trace and text of a managed exception with late binding. Only the
+// type is read from an object that is not an exception
+func readException(exception *IUnknown) (details ManagedException) {
+	exceptionType, err := exception.GetType()
+	if err != nil {
+		return
+	}
+	defer exceptionType.Release()
+	details.Type, _ = exceptionType.GetFullName()
+	for _, property := range []struct {
+		name  string
+		value *string
+	}{{"Message", &details.Message}, {"StackTrace", &details.StackTrace}} {
+		if value, errP := exceptionType.InvokeMember(property.name, BindingFlags_GetProperty|BindingFlags_Public|BindingFlags_Instance, exception); errP == nil {
+			*property.value, _ = value.(string)
+		}
+	}
+	if text, errT := exceptionType.InvokeMember("ToString", BindingFlags_InvokeMethod|BindingFlags_Public|BindingFlags_Instance, exception); errT == nil {
+		details.Details, _ = text.(string)
+	}
+	return
+}
+
+// load loads the raw bytes of an assembly into the domain with Load_3
+func (obj *AppDomain) load(raw []byte) (*Assembly, error) {
+	safeArrayPtr, err := CreateSafeArray(raw)
+	if err != nil {
+		return nil, err
+	}
+	defer SafeArrayDestroy(safeArrayPtr)
+	return obj.Load_3(safeArrayPtr)
+}
@@ -128,10 +128,214 @@ func (obj *Assembly) GetEntryPoint() (pRetVal *MethodInfo, err error) {
 	return
 }
 
+// GetType_2 gets the Type object with the specified full name in the assembly, such as "Namespace.Class". An error is
+// returned when the assembly does not contain the type
+//
+//	virtual HRESULT __stdcall GetType_2 (
+//	/*[in]*/ BSTR name,
+//	/*[out,retval]*/ struct _Type * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.gettype?view=netframework-4.8#System_Reflection_Assembly_GetType_System_String_
+func (obj *Assembly) GetType_2(name string) (assemblyType *Type, err error) {
+	debugPrint("Entering into assembly.GetType_2()...")
+	bstrName, err := SysAllocString(name)
+	if err != nil {
+		return
+	}
+	defer bstrName.Free()
+
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetType_2,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrName.Pointer()),
+		uintptr(unsafe.Pointer(&assemblyType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the Assembly::GetType_2 method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	if assemblyType == nil {
+		err = fmt.Errorf("the %s type was not found in the assembly", name)
+	}
+	return
+}
+
+// GetType gets the Type object with the specified full name in the assembly, such as "Namespace.Class", ignoring the
+// case of the name if ignoreCase is true. When the type is not found, an error is returned if throwOnError is true and
+// a nil Type otherwise
+//
+//	virtual HRESULT __stdcall GetType_4 (
+//	/*[in]*/ BSTR name,
+//	/*[in]*/ VARIANT_BOOL throwOnError,
+//	/*[in]*/ VARIANT_BOOL ignoreCase,
+//	/*[out,retval]*/ struct _Type * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.gettype?view=netframework-4.8#System_Reflection_Assembly_GetType_System_String_System_Boolean_System_Boolean_
+func (obj *Assembly) GetType(name string, throwOnError, ignoreCase bool) (assemblyType *Type, err error) {
+	debugPrint("Entering into assembly.GetType()...")
+	bstrName, err := SysAllocString(name)
+	if err != nil {
+		return
+	}
+	defer bstrName.Free()
+
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetType_4,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrName.Pointer()),
+		uintptr(uint16(variantBool(throwOnError))),
+		uintptr(uint16(variantBool(ignoreCase))),
+		uintptr(unsafe.Pointer(&assemblyType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the Assembly::GetType_4 method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
+
+// GetTypes gets all the types defined in the assembly. When some of them can not be loaded, the CLR throws a
+// ReflectionTypeLoadException: the types that did load are returned along with a *TypeLoadError holding the messages
+// of the loader exceptions
+//
+//	virtual HRESULT __stdcall GetTypes (
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.gettypes?view=netframework-4.8
+func (obj *Assembly) GetTypes() ([]*Type, error) {
+	debugPrint("Entering into assembly.GetTypes()...")
+	return obj.getTypes(obj.vtbl.GetTypes, "GetTypes")
+}
+
+// GetExportedTypes gets the public types defined in the assembly that are visible outside of it. Types that can not be
+// loaded are handled as in GetTypes
+//
+//	virtual HRESULT __stdcall GetExportedTypes (
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.getexportedtypes?view=netframework-4.8
+func (obj *Assembly) GetExportedTypes() ([]*Type, error) {
+	debugPrint("Entering into assembly.GetExportedTypes()...")
+	return obj.getTypes(obj.vtbl.GetExportedTypes, "GetExportedTypes")
+}
+
+// getTypes calls an _Assembly method that returns a SAFEARRAY(_Type*) and reads it
+func (obj *Assembly) getTypes(method uintptr, name string) ([]*Type, error) {
+	var psa *SafeArray
+	hr, _, _ := syscall.SyscallN(
+		method,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&psa)),
+	)
+	if uint32(hr) == COR_E_REFLECTIONTYPELOAD {
+		return readTypeLoadException()
+	}
+	if hr != S_OK {
+		return nil, fmt.Errorf("the Assembly::%s method returned a non-zero HRESULT: 0x%x", name, hr)
+	}
+	return interfacesFromSafeArray[Type](psa)
+}
+
+// readTypeLoadException reads the ReflectionTypeLoadException the CLR set as the error object of the thread. Its Types
+// property holds the types that were loaded, with a null element for each type that could not be, and its
+// LoaderExceptions property the exceptions thrown for those
+func readTypeLoadException() (types []*Type, err error) {
+	errorInfo, err := GetErrorInfo()
+	if err != nil {
+		return nil, fmt.Errorf("there was an error getting the ReflectionTypeLoadException:\r\n%s", err)
+	}
+	// The error object of a managed exception is the exception itself
+	exception := (*IUnknown)(unsafe.Pointer(errorInfo))
+	defer exception.Release()
+
+	loaded, err := exception.getProperty("Types")
+	if err != nil {
+		return nil, err
+	}
+	for _, element := range interfacesOf(loaded) {
+		var loadedType *Type
+		errQ := element.QueryInterface(IID__Type, unsafe.Pointer(&loadedType))
+		element.Release()
+		if errQ == nil {
+			types = append(types, loadedType)
+		}
+	}
+
+	typeLoadError := &TypeLoadError{}
+	loaderExceptions, err := exception.getProperty("LoaderExceptions")
+	if err != nil {
+		return types, err
+	}
+	for _, loaderException := range interfacesOf(loaderExceptions) {
+		message, errM := loaderException.getProperty("Message")
+		loaderException.Release()
+		if errM != nil {
+			message = errM.Error()
+		}
+		typeLoadError.LoaderExceptions = append(typeLoadError.LoaderExceptions, fmt.Sprint(message))
+	}
+	return types, typeLoadError
+}
+
+// CreateInstance creates an instance of the type with the specified full name in the assembly, such as
+// "Namespace.Class", calling the public constructor that matches the types of the arguments. The returned Object must
+// be released by the caller
+//
+//	virtual HRESULT __stdcall CreateInstance_3 (
+//	/*[in]*/ BSTR typeName,
+//	/*[in]*/ VARIANT_BOOL ignoreCase,
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[in]*/ struct _Binder * Binder,
+//	/*[in]*/ SAFEARRAY * args,
+//	/*[in]*/ struct _CultureInfo * culture,
+//	/*[in]*/ SAFEARRAY * activationAttributes,
+//	/*[out,retval]*/ VARIANT * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.createinstance?view=netframework-4.8#System_Reflection_Assembly_CreateInstance_System_String_System_Boolean_System_Reflection_BindingFlags_System_Reflection_Binder_System_Object___System_Globalization_CultureInfo_System_Object___
+func (obj *Assembly) CreateInstance(typeName string, args ...any) (*Object, error) {
+	debugPrint("Entering into assembly.CreateInstance()...")
+	bstrTypeName, err := SysAllocString(typeName)
+	if err != nil {
+		return nil, err
+	}
+	defer bstrTypeName.Free()
+
+	parameters, err := SafeArrayFromSlice(args)
+	if err != nil {
+		return nil, err
+	}
+	defer parameters.Destroy()
+
+	var ret Variant
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.CreateInstance_3,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrTypeName.Pointer()),
+		uintptr(uint16(variantBool(false))),
+		uintptr(instanceMembers|BindingFlags_CreateInstance),
+		0,
+		uintptr(unsafe.Pointer(parameters.SafeArray())),
+		0,
+		0,
+		uintptr(unsafe.Pointer(&ret)),
+	)
+	if hr != S_OK {
+		return nil, fmt.Errorf("the Assembly::CreateInstance_3 method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	result, err := takeVariant(&ret)
+	if err != nil {
+		return nil, err
+	}
+	unknown, ok := result.(*IUnknown)
+	if !ok || unknown == nil {
+		return nil, fmt.Errorf("the %s type was not found in the assembly", typeName)
+	}
+	return newObject(unknown)
+}
+
 func (obj *Assembly) GetFullName() (string, error) {
 	debugPrint("Entering into assembly.GetFullName()...")
 	var err error
-	var pRetValBSTR unsafe.Pointer
+	var pRetValBSTR BSTR
 	hr, _, err := syscall.SyscallN(
 		obj.vtbl.get_FullName,
 		uintptr(unsafe.Pointer(obj)),
@@ -145,5 +349,256 @@ func (obj *Assembly) GetFullName() (string, error) {
 		err = fmt.Errorf("the Assembly::GetFullName method returned a non-zero HRESULT: 0x%x", hr)
 		return "", err
 	}
-	return ReadUnicodeStr(pRetValBSTR), nil
+	defer pRetValBSTR.Free()
+	return pRetValBSTR.String(), nil
+}
+
+// getString calls a method that returns a BSTR and converts it
+func (obj *Assembly) getString(method uintptr, name string) (string, error) {
+	var bstr BSTR
+	hr, _, _ := syscall.SyscallN(
+		method,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&bstr)),
+	)
+	if hr != S_OK {
+		return "", fmt.Errorf("the Assembly::%s method returned a non-zero HRESULT: 0x%x", name, hr)
+	}
+	defer bstr.Free()
+	return bstr.String(), nil
+}
+
+// GetCodeBase returns the location of the assembly as it was originally specified, as a URL such as
+// "file:///C:/Windows/Microsoft.NET/Framework64/v4.0.30319/mscorlib.dll"
+//
+//	virtual HRESULT __stdcall get_CodeBase (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.codebase?view=netframework-4.8
+func (obj *Assembly) GetCodeBase() (string, error) {
+	debugPrint("Entering into assembly.GetCodeBase()...")
+	return obj.getString(obj.vtbl.get_CodeBase, "get_CodeBase")
+}
+
+// GetLocation returns the full path of the loaded file, an empty string for assemblies loaded from memory
+//
+//	virtual HRESULT __stdcall get_Location (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.location?view=netframework-4.8
+func (obj *Assembly) GetLocation() (string, error) {
+	debugPrint("Entering into assembly.GetLocation()...")
+	return obj.getString(obj.vtbl.get_Location, "get_Location")
+}
+
+// GetGlobalAssemblyCache returns true if the assembly was loaded from the global assembly cache
+//
+//	virtual HRESULT __stdcall get_GlobalAssemblyCache (
+//	/*[out,retval]*/ VARIANT_BOOL * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.globalassemblycache?view=netframework-4.8
+func (obj *Assembly) GetGlobalAssemblyCache() (bool, error) {
+	debugPrint("Entering into assembly.GetGlobalAssemblyCache()...")
+	var b int16
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_GlobalAssemblyCache,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&b)),
+	)
+	if hr != S_OK {
+		return false, fmt.Errorf("the Assembly::get_GlobalAssemblyCache method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return b != 0, nil
+}
+
+// GetImageRuntimeVersion returns the version of the CLR saved in the file containing the manifest, such as
+// "v4.0.30319". The property is not part of the _Assembly interface and is read with late binding
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.imageruntimeversion?view=netframework-4.8
+func (obj *Assembly) GetImageRuntimeVersion() (string, error) {
+	debugPrint("Entering into assembly.GetImageRuntimeVersion()...")
+	version, err := (*IUnknown)(unsafe.Pointer(obj)).getProperty("ImageRuntimeVersion")
+	if err != nil {
+		return "", err
+	}
+	s, _ := version.(string)
+	return s, nil
+}
+
+// GetName returns the AssemblyName of the assembly
+//
+//	virtual HRESULT __stdcall GetName (
+//	/*[out,retval]*/ struct _AssemblyName * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.getname?view=netframework-4.8
+func (obj *Assembly) GetName() (*AssemblyName, error) {
+	debugPrint("Entering into assembly.GetName()...")
+	var name *IUnknown
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetName,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&name)),
+	)
+	if hr != S_OK {
+		return nil, fmt.Errorf("the Assembly::GetName method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	defer name.Release()
+	assemblyName, err := readAssemblyName(name)
+	if err != nil {
+		return nil, err
+	}
+	return &assemblyName, nil
+}
+
+// GetReferencedAssemblies returns the AssemblyName of every assembly referenced by the assembly
+//
+//	virtual HRESULT __stdcall GetReferencedAssemblies (
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.getreferencedassemblies?view=netframework-4.8
+func (obj *Assembly) GetReferencedAssemblies() ([]AssemblyName, error) {
+	debugPrint("Entering into assembly.GetReferencedAssemblies()...")
+	var psa *SafeArray
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetReferencedAssemblies,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&psa)),
+	)
+	if hr != S_OK {
+		return nil, fmt.Errorf("the Assembly::GetReferencedAssemblies method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	names, err := interfacesFromSafeArray[IUnknown](psa)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, name := range names {
+			if name != nil {
+				name.Release()
+			}
+		}
+	}()
+
+	references := make([]AssemblyName, 0, len(names))
+	for _, name := range names {
+		if name == nil {
+			continue
+		}
+		reference, err := readAssemblyName(name)
+		if err != nil {
+			return nil, err
+		}
+		references = append(references, reference)
+	}
+	return references, nil
+}
+
+// Info returns the name, location and references of the assembly
+func (obj *Assembly) Info() (info AssemblyInfo, err error) {
+	debugPrint("Entering into assembly.Info()...")
+	name, err := obj.GetName()
+	if err != nil {
+		return
+	}
+	info.AssemblyName = *name
+	if info.FullName, err = obj.GetFullName(); err != nil {
+		return
+	}
+	// Dynamic assemblies throw a NotSupportedException instead of returning a code base or location
+	if info.CodeBase, err = obj.GetCodeBase(); err != nil {
+		info.CodeBase = ""
+	}
+	if info.Location, err = obj.GetLocation(); err != nil {
+		info.Location = ""
+	}
+	if info.GlobalAssemblyCache, err = obj.GetGlobalAssemblyCache(); err != nil {
+		return
+	}
+	if info.ImageRuntimeVersion, err = obj.GetImageRuntimeVersion(); err != nil {
+		return
+	}
+	info.References, err = obj.GetReferencedAssemblies()
+	return
+}
+
+// readAssemblyName reads an AssemblyName with late binding, its _AssemblyName interface has no properties
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assemblyname?view=netframework-4.8
+func readAssemblyName(assemblyName *IUnknown) (name AssemblyName, err error) {
+	nameType, err := assemblyName.GetType()
+	if err != nil {
+		return
+	}
+	defer nameType.Release()
+	get := func(member string, invokeAttr BindingFlags) (any, error) {
+		return nameType.InvokeMember(member, invokeAttr|BindingFlags_Public|BindingFlags_Instance, assemblyName)
+	}
+
+	simpleName, err := get("Name", BindingFlags_GetProperty)
+	if err != nil {
+		return
+	}
+	name.Name, _ = simpleName.(string)
+
+	version, err := get("Version", BindingFlags_GetProperty)
+	if err != nil {
+		return
+	}
+	if version, ok := version.(*IUnknown); ok && version != nil {
+		name.Version, err = readVersion(version)
+		version.Release()
+		if err != nil {
+			return
+		}
+	}
+
+	// CultureName was added in .NET Framework 4.5, before that the name of the CultureInfo is read
+	culture, err := get("CultureName", BindingFlags_GetProperty)
+	if err != nil {
+		if culture, err = get("CultureInfo", BindingFlags_GetProperty); err != nil {
+			return
+		}
+		if cultureInfo, ok := culture.(*IUnknown); ok && cultureInfo != nil {
+			culture, err = cultureInfo.getProperty("Name")
+			cultureInfo.Release()
+			if err != nil {
+				return
+			}
+		}
+	}
+	if culture, ok := culture.(string); ok {
+		name.Culture = culture
+		if culture == "" {
+			name.Culture = "neutral"
+		}
+	}
+
+	token, err := get("GetPublicKeyToken", BindingFlags_InvokeMethod)
+	if err != nil {
+		return
+	}
+	if token, ok := token.([]byte); ok {
+		name.PublicKeyToken = append([]byte{}, token...)
+	}
+
+	architecture, err := get("ProcessorArchitecture", BindingFlags_GetProperty)
+	if err != nil {
+		return
+	}
+	if architecture, ok := architecture.(int32); ok {
+		name.ProcessorArchitecture = ProcessorArchitecture(architecture)
+	}
+	return
+}
+
+// readVersion reads the components of a managed Version with late binding
+func readVersion(version *IUnknown) (*Version, error) {
+	var components [4]int
+	for i, component := range []string{"Major", "Minor", "Build", "Revision"} {
+		value, err := version.getProperty(component)
+		if err != nil {
+			return nil, err
+		}
+		n, _ := value.(int32)
+		components[i] = int(n)
+	}
+	return &Version{Major: components[0], Minor: components[1], Build: components[2], Revision: components[3]}, nil
 }
@@ -0,0 +1,380 @@
+package clr
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ProcessorArchitecture is the processor and bitness targeted by an assembly
+//
+//	enum ProcessorArchitecture
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.processorarchitecture?view=netframework-4.8
+type ProcessorArchitecture int32
+
+const (
+	ProcessorArchitecture_None  ProcessorArchitecture = 0x0000
+	ProcessorArchitecture_MSIL  ProcessorArchitecture = 0x0001
+	ProcessorArchitecture_X86   ProcessorArchitecture = 0x0002
+	ProcessorArchitecture_IA64  ProcessorArchitecture = 0x0003
+	ProcessorArchitecture_Amd64 ProcessorArchitecture = 0x0004
+	ProcessorArchitecture_Arm   ProcessorArchitecture = 0x0005
+)
+
+// processorArchitectureNames are the names used by display names for each ProcessorArchitecture
+var processorArchitectureNames = map[ProcessorArchitecture]string{
+	ProcessorArchitecture_None:  "None",
+	ProcessorArchitecture_MSIL:  "MSIL",
+	ProcessorArchitecture_X86:   "X86",
+	ProcessorArchitecture_IA64:  "IA64",
+	ProcessorArchitecture_Amd64: "Amd64",
+	ProcessorArchitecture_Arm:   "Arm",
+}
+
+func (p ProcessorArchitecture) String() string {
+	if name, ok := processorArchitectureNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("ProcessorArchitecture(%d)", int32(p))
+}
+
+// Version is the four part version number of an assembly. Build and Revision are -1 when they are not defined
+// https://docs.microsoft.com/en-us/dotnet/api/system.version?view=netframework-4.8
+type Version struct {
+	Major, Minor, Build, Revision int
+}
+
+// String returns the defined components of the version separated by dots, such as "4.0.0.0"
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d", v.Major, v.Minor)
+	if v.Build >= 0 {
+		s += fmt.Sprintf(".%d", v.Build)
+		if v.Revision >= 0 {
+			s += fmt.Sprintf(".%d", v.Revision)
+		}
+	}
+	return s
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is lower than, equal to or greater than w. An undefined
+// component is lower than any defined one, as in the managed Version.CompareTo method
+func (v Version) Compare(w Version) int {
+	for _, c := range [][2]int{{v.Major, w.Major}, {v.Minor, w.Minor}, {v.Build, w.Build}, {v.Revision, w.Revision}} {
+		switch {
+		case c[0] < c[1]:
+			return -1
+		case c[0] > c[1]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AssemblyName is the identity of an assembly, the parts of its display name
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assemblyname?view=netframework-4.8
+type AssemblyName struct {
+	// Name is the simple name of the assembly, such as "mscorlib"
+	Name string
+	// Version is the version of the assembly, nil when it is not specified
+	Version *Version
+	// Culture is the name of the culture of the assembly, "neutral" for culture neutral assemblies and empty when it
+	// is not specified
+	Culture string
+	// PublicKeyToken is the last 8 bytes of the SHA-1 hash of the public key of a strong named assembly, an empty
+	// slice for assemblies that are not strong named and nil when it is not specified
+	PublicKeyToken []byte
+	// ProcessorArchitecture is the processor targeted by the assembly, ProcessorArchitecture_None when it is not
+	// specified
+	ProcessorArchitecture ProcessorArchitecture
+}
+
+// ParseAssemblyName parses a Fusion display name such as
+// "mscorlib, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089, ProcessorArchitecture=MSIL". Only the
+// simple name is required, it can be quoted or escape characters with a backslash. The Version, Culture,
+// PublicKeyToken and ProcessorArchitecture attributes are case-insensitive and can be in any order, other attributes,
+// such as Retargetable, are ignored. A Version can have two to four components and PublicKeyToken=null is an empty
+// token
+// https://docs.microsoft.com/en-us/dotnet/standard/assembly/names
+func ParseAssemblyName(displayName string) (name AssemblyName, err error) {
+	parts, err := splitDisplayName(displayName)
+	if err != nil {
+		return name, fmt.Errorf("%q is not a valid assembly name: %w", displayName, err)
+	}
+	if parts[0] == "" {
+		return name, fmt.Errorf("%q is not a valid assembly name: the name is empty", displayName)
+	}
+	name.Name = parts[0]
+
+	seen := make(map[string]bool)
+	for _, part := range parts[1:] {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return name, fmt.Errorf("%q is not a valid assembly name: %q is not a key=value attribute", displayName, part)
+		}
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		if seen[key] {
+			return name, fmt.Errorf("%q is not a valid assembly name: the %s attribute is repeated", displayName, key)
+		}
+		seen[key] = true
+		switch key {
+		case "version":
+			name.Version, err = parseVersion(value)
+		case "culture":
+			name.Culture = value
+			if strings.EqualFold(value, "neutral") || value == "" {
+				name.Culture = "neutral"
+			}
+		case "publickeytoken":
+			name.PublicKeyToken, err = parsePublicKeyToken(value)
+		case "processorarchitecture":
+			name.ProcessorArchitecture, err = parseProcessorArchitecture(value)
+		}
+		if err != nil {
+			return name, fmt.Errorf("%q is not a valid assembly name: %w", displayName, err)
+		}
+	}
+	return name, nil
+}
+
+// String formats the display name of the assembly with the attributes that are specified, such as
+// "mscorlib, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089"
+func (name AssemblyName) String() string {
+	var b strings.Builder
+	b.WriteString(escapeDisplayName(name.Name))
+	if name.Version != nil {
+		fmt.Fprintf(&b, ", Version=%s", name.Version)
+	}
+	if name.Culture != "" {
+		fmt.Fprintf(&b, ", Culture=%s", name.Culture)
+	}
+	if name.PublicKeyToken != nil {
+		if len(name.PublicKeyToken) == 0 {
+			b.WriteString(", PublicKeyToken=null")
+		} else {
+			fmt.Fprintf(&b, ", PublicKeyToken=%x", name.PublicKeyToken)
+		}
+	}
+	if name.ProcessorArchitecture != ProcessorArchitecture_None {
+		fmt.Fprintf(&b, ", ProcessorArchitecture=%s", name.ProcessorArchitecture)
+	}
+	return b.String()
+}
+
+// IsPartial reports whether the name is missing any of the version, culture or public key token that make up a
+// fully qualified name
+func (name AssemblyName) IsPartial() bool {
+	return name.Version == nil || name.Culture == "" || name.PublicKeyToken == nil
+}
+
+// Matches reports whether the assembly named candidate satisfies the reference name, following the partial binding
+// rules: the simple names are compared ignoring case and every attribute specified in the reference must be equal in
+// the candidate, those that are not specified match anything. Likewise a version with only two or three components
+// only compares those
+func (name AssemblyName) Matches(candidate AssemblyName) bool {
+	if !strings.EqualFold(name.Name, candidate.Name) {
+		return false
+	}
+	if name.Version != nil {
+		if candidate.Version == nil {
+			return false
+		}
+		want, got := *name.Version, *candidate.Version
+		if want.Build < 0 {
+			got.Build, got.Revision = -1, -1
+		} else if want.Revision < 0 {
+			got.Revision = -1
+		}
+		if want.Compare(got) != 0 {
+			return false
+		}
+	}
+	if name.Culture != "" && !strings.EqualFold(name.Culture, candidate.Culture) {
+		return false
+	}
+	if name.PublicKeyToken != nil && (candidate.PublicKeyToken == nil || !bytes.Equal(name.PublicKeyToken, candidate.PublicKeyToken)) {
+		return false
+	}
+	if name.ProcessorArchitecture != ProcessorArchitecture_None && name.ProcessorArchitecture != candidate.ProcessorArchitecture {
+		return false
+	}
+	return true
+}
+
+// splitDisplayName splits a display name on the commas that are not quoted or escaped and unescapes the parts. The
+// spaces around a part are trimmed, except those between the quotes of a quoted name or value
+func splitDisplayName(displayName string) ([]string, error) {
+	var parts []string
+	var part strings.Builder
+	var quote rune
+	// quotedEnd is the length of the part when its closing quote was read, -1 if it isn't quoted
+	quotedEnd := -1
+	escaped := false
+	for _, c := range displayName {
+		switch {
+		case escaped:
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			}
+			part.WriteRune(c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+				quotedEnd = part.Len()
+			} else {
+				part.WriteRune(c)
+			}
+		case (c == '"' || c == '\'') && quotedEnd < 0 && startsValue(part.String()):
+			start := strings.TrimSpace(part.String())
+			part.Reset()
+			part.WriteString(start)
+			quote = c
+		case c == ',':
+			parts = append(parts, trimPart(part.String(), quotedEnd))
+			part.Reset()
+			quotedEnd = -1
+		default:
+			part.WriteRune(c)
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("the name ends with an escape character")
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("a quote is not closed")
+	}
+	return append(parts, trimPart(part.String(), quotedEnd)), nil
+}
+
+// startsValue reports whether a quote read after part starts a quoted name or attribute value
+func startsValue(part string) bool {
+	part = strings.TrimSpace(part)
+	return part == "" || strings.HasSuffix(part, "=")
+}
+
+// trimPart trims the spaces around a part of a display name, quoted parts only lose those after the closing quote
+func trimPart(part string, quotedEnd int) string {
+	if quotedEnd < 0 {
+		return strings.TrimSpace(part)
+	}
+	return part[:quotedEnd] + strings.TrimSpace(part[quotedEnd:])
+}
+
+// escapeDisplayName escapes the characters of a simple name that have a meaning in a display name and quotes names
+// that start or end with a space
+func escapeDisplayName(name string) string {
+	var b strings.Builder
+	for _, c := range name {
+		switch c {
+		case ',', '=', '"', '\'', '\\':
+			b.WriteRune('\\')
+			b.WriteRune(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	if strings.TrimSpace(name) != name {
+		return `"` + b.String() + `"`
+	}
+	return b.String()
+}
+
+// parseVersion parses a version with two to four components between 0 and 65535
+func parseVersion(s string) (*Version, error) {
+	components := strings.Split(s, ".")
+	if len(components) < 2 || len(components) > 4 {
+		return nil, fmt.Errorf("the version %q does not have two to four components", s)
+	}
+	v := []int{-1, -1, -1, -1}
+	for i, component := range components {
+		n, err := strconv.ParseUint(component, 10, 16)
+		if err != nil || n == 65535 {
+			return nil, fmt.Errorf("the version %q has an invalid component %q", s, component)
+		}
+		v[i] = int(n)
+	}
+	return &Version{Major: v[0], Minor: v[1], Build: v[2], Revision: v[3]}, nil
+}
+
+// parsePublicKeyToken parses the 16 hexadecimal digits of a public key token or null
+func parsePublicKeyToken(s string) ([]byte, error) {
+	if strings.EqualFold(s, "null") {
+		return []byte{}, nil
+	}
+	token, err := hex.DecodeString(s)
+	if err != nil || len(token) != 8 {
+		return nil, fmt.Errorf("the public key token %q is not 16 hexadecimal digits", s)
+	}
+	return token, nil
+}
+
+// parseProcessorArchitecture parses the name of a ProcessorArchitecture ignoring case
+func parseProcessorArchitecture(s string) (ProcessorArchitecture, error) {
+	for architecture, name := range processorArchitectureNames {
+		if strings.EqualFold(s, name) {
+			return architecture, nil
+		}
+	}
+	return ProcessorArchitecture_None, fmt.Errorf("%q is not a processor architecture", s)
+}
+
+// AssemblyInfo describes an assembly loaded into an AppDomain
+type AssemblyInfo struct {
+	AssemblyName
+	// FullName is the display name of the assembly
+	FullName string
+	// CodeBase is the location of the assembly as it was originally specified, as a URL
+	CodeBase string
+	// Location is the path of the loaded file, empty for assemblies loaded from memory
+	Location string
+	// GlobalAssemblyCache is true if the assembly was loaded from the global assembly cache
+	GlobalAssemblyCache bool
+	// ImageRuntimeVersion is the version of the CLR the assembly was built for, such as "v4.0.30319"
+	ImageRuntimeVersion string
+	// References are the names of the assemblies the assembly references
+	References []AssemblyName
+}
+
+// AssemblyInventory is a list of loaded assemblies. It implements sort.Interface to order the assemblies by name,
+// ignoring case, then version and location
+type AssemblyInventory []AssemblyInfo
+
+func (inv AssemblyInventory) Len() int {
+	return len(inv)
+}
+
+func (inv AssemblyInventory) Less(i, j int) bool {
+	a, b := inv[i], inv[j]
+	if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+		return c < 0
+	}
+	if a.Version != nil && b.Version != nil {
+		if c := a.Version.Compare(*b.Version); c != 0 {
+			return c < 0
+		}
+	} else if a.Version != b.Version {
+		// Assemblies without a version come first
+		return a.Version == nil
+	}
+	return a.Location < b.Location
+}
+
+func (inv AssemblyInventory) Swap(i, j int) {
+	inv[i], inv[j] = inv[j], inv[i]
+}
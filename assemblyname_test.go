@@ -0,0 +1,246 @@
+package clr
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func version(major, minor, build, revision int) *Version {
+	return &Version{Major: major, Minor: minor, Build: build, Revision: revision}
+}
+
+func TestParseAssemblyName(t *testing.T) {
+	token := []byte{0xb7, 0x7a, 0x5c, 0x56, 0x19, 0x34, 0xe0, 0x89}
+	tests := []struct {
+		displayName string
+		want        AssemblyName
+	}{
+		{"Foo", AssemblyName{Name: "Foo"}},
+		{"  Foo  ", AssemblyName{Name: "Foo"}},
+		{
+			"mscorlib, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089, ProcessorArchitecture=MSIL",
+			AssemblyName{Name: "mscorlib", Version: version(4, 0, 0, 0), Culture: "neutral", PublicKeyToken: token, ProcessorArchitecture: ProcessorArchitecture_MSIL},
+		},
+		// Attributes are case-insensitive, in any order, and unknown ones are ignored
+		{
+			"mscorlib,processorarchitecture=amd64,PUBLICKEYTOKEN=B77A5C561934E089 , culture=NEUTRAL,Retargetable=Yes,version=4.0",
+			AssemblyName{Name: "mscorlib", Version: version(4, 0, -1, -1), Culture: "neutral", PublicKeyToken: token, ProcessorArchitecture: ProcessorArchitecture_Amd64},
+		},
+		{"Tools.resources, Culture=fr-FR", AssemblyName{Name: "Tools.resources", Culture: "fr-FR"}},
+		{"Foo, Culture=", AssemblyName{Name: "Foo", Culture: "neutral"}},
+		{"Foo, Version=1.2.3", AssemblyName{Name: "Foo", Version: version(1, 2, 3, -1)}},
+		{"Foo, PublicKeyToken=null", AssemblyName{Name: "Foo", PublicKeyToken: []byte{}}},
+		{"Foo, PublicKeyToken=NULL", AssemblyName{Name: "Foo", PublicKeyToken: []byte{}}},
+		// Quoted names and values keep their spaces and commas
+		{`"Foo, Bar"`, AssemblyName{Name: "Foo, Bar"}},
+		{`' Foo ' , Culture="en-US"`, AssemblyName{Name: " Foo ", Culture: "en-US"}},
+		{`Foo, Culture = 'neutral'`, AssemblyName{Name: "Foo", Culture: "neutral"}},
+		// Escaped characters
+		{`Foo\,Bar`, AssemblyName{Name: "Foo,Bar"}},
+		{`Foo\=\"Bar\'\\`, AssemblyName{Name: `Foo="Bar'\`}},
+		{`Foo\tBar\nBaz\rQux`, AssemblyName{Name: "Foo\tBar\nBaz\rQux"}},
+		{`"Foo\"Bar"`, AssemblyName{Name: `Foo"Bar`}},
+		// A quote inside a name is a plain character
+		{`Foo"Bar`, AssemblyName{Name: `Foo"Bar`}},
+	}
+	for _, tt := range tests {
+		got, err := ParseAssemblyName(tt.displayName)
+		if err != nil {
+			t.Errorf("ParseAssemblyName(%q) returned an error: %s", tt.displayName, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseAssemblyName(%q) = %#v, want %#v", tt.displayName, got, tt.want)
+		}
+	}
+}
+
+func TestParseAssemblyNameInvalid(t *testing.T) {
+	for _, displayName := range []string{
+		"",
+		"  ",
+		", Version=1.0",
+		`""`,
+		"Foo, Version",
+		"Foo, Version=1.0, Version=2.0",
+		"Foo, Culture=neutral, culture=en-US",
+		"Foo, Version=1",
+		"Foo, Version=1.2.3.4.5",
+		"Foo, Version=1.x",
+		"Foo, Version=1.-2",
+		"Foo, Version=1.65535",
+		"Foo, Version=1.65536",
+		"Foo, PublicKeyToken=b77a5c561934e0",
+		"Foo, PublicKeyToken=b77a5c561934e08900",
+		"Foo, PublicKeyToken=zz7a5c561934e089",
+		"Foo, ProcessorArchitecture=Sparc",
+		`"Foo`,
+		`Foo, Culture="neutral`,
+		`Foo\`,
+	} {
+		if got, err := ParseAssemblyName(displayName); err == nil {
+			t.Errorf("ParseAssemblyName(%q) = %#v, want an error", displayName, got)
+		}
+	}
+}
+
+func TestAssemblyNameString(t *testing.T) {
+	tests := []struct {
+		name AssemblyName
+		want string
+	}{
+		{AssemblyName{Name: "Foo"}, "Foo"},
+		{
+			AssemblyName{Name: "mscorlib", Version: version(4, 0, 0, 0), Culture: "neutral", PublicKeyToken: []byte{0xb7, 0x7a, 0x5c, 0x56, 0x19, 0x34, 0xe0, 0x89}, ProcessorArchitecture: ProcessorArchitecture_X86},
+			"mscorlib, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089, ProcessorArchitecture=X86",
+		},
+		{AssemblyName{Name: "Foo", Version: version(1, 2, -1, -1), PublicKeyToken: []byte{}}, "Foo, Version=1.2, PublicKeyToken=null"},
+		{AssemblyName{Name: "Foo, Bar=\"Baz\""}, `Foo\, Bar\=\"Baz\"`},
+		{AssemblyName{Name: " Foo\t"}, `" Foo\t"`},
+	}
+	for _, tt := range tests {
+		if got := tt.name.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestAssemblyNameRoundTrip(t *testing.T) {
+	for _, displayName := range []string{
+		"Foo",
+		"mscorlib, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089, ProcessorArchitecture=MSIL",
+		"Tools.resources, Version=1.2.3, Culture=fr-FR, PublicKeyToken=null",
+		`Foo\,Bar\=Baz\'\"\\`,
+		`" Foo, Bar "`,
+		`'Tab\tName'`,
+	} {
+		name, err := ParseAssemblyName(displayName)
+		if err != nil {
+			t.Errorf("ParseAssemblyName(%q) returned an error: %s", displayName, err)
+			continue
+		}
+		again, err := ParseAssemblyName(name.String())
+		if err != nil {
+			t.Errorf("ParseAssemblyName(%q) of the formatted name returned an error: %s", name.String(), err)
+			continue
+		}
+		if !reflect.DeepEqual(again, name) {
+			t.Errorf("%q was formatted as %q, which parses to %#v instead of %#v", displayName, name.String(), again, name)
+		}
+	}
+}
+
+func TestAssemblyNameMatches(t *testing.T) {
+	full := "Foo, Version=1.2.3.4, Culture=neutral, PublicKeyToken=b77a5c561934e089, ProcessorArchitecture=MSIL"
+	tests := []struct {
+		reference string
+		candidate string
+		want      bool
+	}{
+		// A partial name only matches the same simple name, "Foo" doesn't match "FooBar"
+		{"Foo", "FooBar", false},
+		{"Foo", "FooBar, Version=1.0.0.0", false},
+		{"FooBar", "Foo", false},
+		{"Foo", "Foo.Bar", false},
+		{"Foo", full, true},
+		{"foo", full, true},
+		{"Foo", "Foo", true},
+		// Versions only compare the components of the reference
+		{"Foo, Version=1.2", full, true},
+		{"Foo, Version=1.3", full, false},
+		{"Foo, Version=1.2.3", full, true},
+		{"Foo, Version=1.2.4", full, false},
+		{"Foo, Version=1.2.3.4", full, true},
+		{"Foo, Version=1.2.3.5", full, false},
+		{"Foo, Version=1.2.3.0", "Foo, Version=1.2.3", false},
+		{"Foo, Version=1.2", "Foo", false},
+		{"Foo", "Foo, Version=1.2", true},
+		{"Foo, Culture=NEUTRAL", full, true},
+		{"Foo, Culture=en-US", full, false},
+		{"Foo, Culture=neutral", "Foo", false},
+		{"Foo, PublicKeyToken=B77A5C561934E089", full, true},
+		{"Foo, PublicKeyToken=null", full, false},
+		{"Foo, PublicKeyToken=null", "Foo, PublicKeyToken=null", true},
+		{"Foo, PublicKeyToken=null", "Foo", false},
+		{"Foo, ProcessorArchitecture=msil", full, true},
+		{"Foo, ProcessorArchitecture=X86", full, false},
+		{full, full, true},
+		{full, "Foo", false},
+	}
+	for _, tt := range tests {
+		reference, err := ParseAssemblyName(tt.reference)
+		if err != nil {
+			t.Fatalf("ParseAssemblyName(%q) returned an error: %s", tt.reference, err)
+		}
+		candidate, err := ParseAssemblyName(tt.candidate)
+		if err != nil {
+			t.Fatalf("ParseAssemblyName(%q) returned an error: %s", tt.candidate, err)
+		}
+		if got := reference.Matches(candidate); got != tt.want {
+			t.Errorf("%q.Matches(%q) = %t, want %t", tt.reference, tt.candidate, got, tt.want)
+		}
+	}
+}
+
+func TestAssemblyNameIsPartial(t *testing.T) {
+	tests := map[string]bool{
+		"Foo":                               true,
+		"Foo, Version=1.0.0.0":              true,
+		"Foo, Version=1.0, Culture=neutral": true,
+		"Foo, Version=1.0.0.0, Culture=neutral, PublicKeyToken=null":             false,
+		"Foo, Version=1.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089": false,
+	}
+	for displayName, want := range tests {
+		name, err := ParseAssemblyName(displayName)
+		if err != nil {
+			t.Fatalf("ParseAssemblyName(%q) returned an error: %s", displayName, err)
+		}
+		if got := name.IsPartial(); got != want {
+			t.Errorf("%q.IsPartial() = %t, want %t", displayName, got, want)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	tests := []struct {
+		v, w    *Version
+		compare int
+		str     string
+	}{
+		{version(1, 2, -1, -1), version(1, 2, 0, -1), -1, "1.2"},
+		{version(1, 2, 3, -1), version(1, 2, 3, 0), -1, "1.2.3"},
+		{version(1, 2, 3, 4), version(1, 2, 3, 4), 0, "1.2.3.4"},
+		{version(2, 0, 0, 0), version(1, 9, 9, 9), 1, "2.0.0.0"},
+	}
+	for _, tt := range tests {
+		if got := tt.v.Compare(*tt.w); got != tt.compare {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.v, tt.w, got, tt.compare)
+		}
+		if got := tt.w.Compare(*tt.v); got != -tt.compare {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.w, tt.v, got, -tt.compare)
+		}
+		if got := tt.v.String(); got != tt.str {
+			t.Errorf("String() = %q, want %q", got, tt.str)
+		}
+	}
+}
+
+func TestAssemblyInventorySort(t *testing.T) {
+	inventory := AssemblyInventory{
+		{AssemblyName: AssemblyName{Name: "foo", Version: version(2, 0, 0, 0)}},
+		{AssemblyName: AssemblyName{Name: "Bar"}, Location: "b"},
+		{AssemblyName: AssemblyName{Name: "Foo", Version: version(1, 0, 0, 0)}},
+		{AssemblyName: AssemblyName{Name: "bar"}, Location: "a"},
+		{AssemblyName: AssemblyName{Name: "Foo"}},
+	}
+	sort.Sort(inventory)
+	var got []string
+	for _, info := range inventory {
+		got = append(got, info.AssemblyName.String()+"@"+info.Location)
+	}
+	want := []string{"bar@a", "Bar@b", "Foo@", "Foo, Version=1.0.0.0@", "foo, Version=2.0.0.0@"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("the sorted inventory is %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,96 @@
+package clr
+
+import (
+	"fmt"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// BSTR is an OLE Automation string, a pointer to UTF-16 characters that is preceded by the length of the string in
+// bytes and followed by a null character. Because of the length prefix a BSTR can hold embedded null characters.
+// A BSTR allocated by SysAllocString, or returned by a COM method as an [out] parameter, is owned by the caller who
+// must deallocate it with Free. The zero value is a NULL BSTR, which COM treats as an empty string.
+// https://docs.microsoft.com/en-us/previous-versions/windows/desktop/automat/bstr
+type BSTR struct {
+	ptr unsafe.Pointer
+}
+
+// Pointer returns the address of the BSTR's first character, the value passed to and returned by COM methods
+func (b BSTR) Pointer() unsafe.Pointer {
+	return b.ptr
+}
+
+// Len returns the number of UTF-16 characters in the BSTR, without the terminating null character
+func (b BSTR) Len() int {
+	return SysStringLen(b)
+}
+
+// String converts the BSTR to a Go string using its length prefix, so embedded null characters are preserved
+func (b BSTR) String() string {
+	if b.ptr == nil {
+		return ""
+	}
+	// The length prefix is the number of bytes in the string, without the terminating null character
+	n := *(*uint32)(unsafe.Add(b.ptr, -4)) / 2
+	return string(utf16.Decode(unsafe.Slice((*uint16)(b.ptr), n)))
+}
+
+// Free deallocates the BSTR with SysFreeString and sets it to NULL. Freeing a NULL BSTR does nothing
+func (b *BSTR) Free() {
+	if b.ptr == nil {
+		return
+	}
+	SysFreeString(*b)
+	b.ptr = nil
+}
+
+// SysAllocString converts a Go string to a BSTR, that is a unicode string prefixed with its length.
+// Allocates a new string and copies the passed string into it, embedded null characters are kept.
+// The caller owns the returned BSTR and must deallocate it with Free.
+//
+//	BSTR SysAllocStringLen(
+//	  const OLECHAR *strIn,
+//	  UINT          ui
+//	);
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/oleauto/nf-oleauto-sysallocstringlen
+func SysAllocString(str string) (BSTR, error) {
+	debugPrint("Entering into bstr.SysAllocString()...")
+
+	input := utf16.Encode([]rune(str))
+	var strIn *uint16
+	if len(input) > 0 {
+		strIn = &input[0]
+	}
+	bstr := oleAuto.SysAllocStringLen(strIn, uint32(len(input)))
+	if bstr == nil {
+		return BSTR{}, fmt.Errorf("the OleAut32!SysAllocStringLen function returned NULL and the string was not allocated")
+	}
+	return BSTR{bstr}, nil
+}
+
+// SysFreeString deallocates a string allocated previously by SysAllocString
+//
+//	void SysFreeString(
+//	  BSTR bstrString
+//	);
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/oleauto/nf-oleauto-sysfreestring
+func SysFreeString(bstr BSTR) {
+	debugPrint("Entering into bstr.SysFreeString()...")
+	oleAuto.SysFreeString(bstr.ptr)
+}
+
+// SysStringLen returns the number of characters in a BSTR, 0 for a NULL BSTR
+//
+//	UINT SysStringLen(
+//	  BSTR pbstr
+//	);
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/oleauto/nf-oleauto-sysstringlen
+func SysStringLen(bstr BSTR) int {
+	if bstr.ptr == nil {
+		return 0
+	}
+	return int(oleAuto.SysStringLen(bstr.ptr))
+}
@@ -0,0 +1,17 @@
+//go:build windows && 386
+// +build windows,386
+
+package clr
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// appendVariantArg appends the words that pass the VARIANT v by value to a COM method. The __stdcall calling
+// convention copies the 16 bytes VARIANT onto the stack, as four consecutive words
+// https://docs.microsoft.com/en-us/cpp/cpp/stdcall
+func appendVariantArg(args []uintptr, v *Variant, pinner *runtime.Pinner) []uintptr {
+	words := *(*[4]uintptr)(unsafe.Pointer(v))
+	return append(args, words[:]...)
+}
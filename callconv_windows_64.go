@@ -0,0 +1,20 @@
+//go:build windows && (amd64 || arm64)
+// +build windows
+// +build amd64 arm64
+
+package clr
+
+import (
+	"runtime"
+	"unsafe"
+)
+
+// appendVariantArg appends the words that pass the VARIANT v by value to a COM method. The x64 and ARM64 calling
+// conventions pass structures larger than 16 bytes, such as the 24 bytes VARIANT, by reference to a copy made by the
+// caller that the callee is free to modify. v must be that copy, it is pinned with pinner for the duration of the call
+// https://docs.microsoft.com/en-us/cpp/build/x64-calling-convention#parameter-passing
+// https://docs.microsoft.com/en-us/cpp/build/arm64-windows-abi-conventions#parameter-passing
+func appendVariantArg(args []uintptr, v *Variant, pinner *runtime.Pinner) []uintptr {
+	pinner.Pin(v)
+	return append(args, uintptr(unsafe.Pointer(v)))
+}
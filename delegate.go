@@ -0,0 +1,290 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+)
+
+// systemCoreNames are the display names of the System.Core assemblies of the .NET Framework 4 and 3.5, which hold
+// System.Linq.Expressions
+var systemCoreNames = []string{
+	"System.Core, Version=4.0.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089",
+	"System.Core, Version=3.5.0.0, Culture=neutral, PublicKeyToken=b77a5c561934e089",
+}
+
+// eventSink is the Go side of a delegate created by newDelegate, it is exposed to managed code as a DispatchObject
+type eventSink struct {
+	handler func(args []any) Variant
+}
+
+// Handle is called through IDispatch by the delegate with its arguments, which are only valid during the call. The
+// returned Variant is the result of the delegate
+func (s *eventSink) Handle(args ...any) Variant {
+	return s.handler(args)
+}
+
+// newDelegate creates a managed delegate of the type delegateType that calls handler with its arguments. The delegate
+// is compiled from an expression tree equivalent to
+//
+//	(sender, e) => (ReturnType)sink.GetType().InvokeMember("Handle", BindingFlags.InvokeMethod, null, sink, new object[] { sender, e })
+//
+// where sink is an eventSink DispatchObject. The typed arrays of Expression.NewArrayInit, Expression.Call and
+// Expression.Lambda can't be passed through COM, which turns them into object[], so the arguments of their params
+// arrays are passed one by one and the array is built by the binder of Type.InvokeMember. The returned interface
+// pointer must be released by the caller
+func newDelegate(domain *AppDomain, delegateType *Type, handler func(args []any) Variant) (delegate *IUnknown, err error) {
+	debugPrint("Entering into delegate.newDelegate()...")
+	b, err := newExpressionBuilder(domain, delegateType)
+	if err != nil {
+		return nil, err
+	}
+	defer b.release()
+
+	invoke, err := delegateType.GetMethod("Invoke", BindingFlags_Public|BindingFlags_Instance)
+	if err != nil {
+		return nil, err
+	}
+	defer invoke.Release()
+	parameterTypes, err := b.parameterTypes(invoke)
+	if err != nil {
+		return nil, err
+	}
+	returnType, err := invoke.GetReturnType()
+	if err != nil {
+		return nil, err
+	}
+	defer returnType.Release()
+	returnTypeName, err := returnType.GetFullName()
+	if err != nil {
+		return nil, err
+	}
+
+	sink, err := NewDispatchObject(&eventSink{handler: handler})
+	if err != nil {
+		return nil, err
+	}
+	// The runtime callable wrapper created for the constant holds its own reference on the sink
+	target, err := b.call("Constant", sink, b.objectType)
+	sink.Release()
+	if err != nil {
+		return nil, err
+	}
+
+	parameters := make([]any, len(parameterTypes))
+	boxed := []any{b.objectType}
+	for i, parameterType := range parameterTypes {
+		if parameters[i], err = b.call("Parameter", parameterType, fmt.Sprintf("arg%d", i)); err != nil {
+			return nil, err
+		}
+		converted, errC := b.call("Convert", parameters[i], b.objectType)
+		if errC != nil {
+			return nil, errC
+		}
+		boxed = append(boxed, converted)
+	}
+	args, err := b.call("NewArrayInit", boxed...)
+	if err != nil {
+		return nil, err
+	}
+
+	targetType, err := b.call("Call", target, b.getType)
+	if err != nil {
+		return nil, err
+	}
+	name, err := b.call("Constant", "Handle")
+	if err != nil {
+		return nil, err
+	}
+	flags, err := b.call("Constant", int32(BindingFlags_InvokeMethod))
+	if err != nil {
+		return nil, err
+	}
+	if flags, err = b.call("Convert", flags, b.bindingFlagsType); err != nil {
+		return nil, err
+	}
+	binder, err := b.call("Constant", nil, b.binderType)
+	if err != nil {
+		return nil, err
+	}
+	body, err := b.call("Call", targetType, b.invokeMember, name, flags, binder, target, args)
+	if err != nil {
+		return nil, err
+	}
+	if returnTypeName != "System.Void" {
+		if body, err = b.call("Convert", body, returnType); err != nil {
+			return nil, err
+		}
+	}
+
+	lambda, err := b.call("Lambda", append([]any{delegateType, body}, parameters...)...)
+	if err != nil {
+		return nil, err
+	}
+	// The lambda is an Expression<TDelegate>, a generic type whose wrapper doesn't expose _Object, so Compile is
+	// called through LambdaExpression
+	compiled, err := b.lambdaType.InvokeMember("Compile", BindingFlags_InvokeMethod|BindingFlags_Public|BindingFlags_Instance, lambda)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error compiling the delegate:\n%s", err)
+	}
+	delegate, ok := compiled.(*IUnknown)
+	if !ok || delegate == nil {
+		releaseInterfaces(compiled)
+		return nil, fmt.Errorf("LambdaExpression.Compile returned a %T instead of a delegate", compiled)
+	}
+	return delegate, nil
+}
+
+// expressionBuilder calls the factory methods of System.Linq.Expressions.Expression and keeps the nodes it creates
+// until release is called
+type expressionBuilder struct {
+	expressionType   *Type
+	lambdaType       *Type
+	objectType       *Type
+	bindingFlagsType *Type
+	binderType       *Type
+	// getType is Object.GetType and invokeMember is Type.InvokeMember(string, BindingFlags, Binder, object, object[])
+	getType      *MethodInfo
+	invokeMember *MethodInfo
+	// owned are the references held on the created nodes
+	owned []*IUnknown
+}
+
+// newExpressionBuilder loads System.Core into the domain and looks up the types and methods used to build delegates.
+// The mscorlib types are found through the type of the delegate
+func newExpressionBuilder(domain *AppDomain, delegateType *Type) (b *expressionBuilder, err error) {
+	b = &expressionBuilder{}
+	defer func() {
+		if err != nil {
+			b.release()
+		}
+	}()
+
+	var systemCore *Assembly
+	for _, name := range systemCoreNames {
+		if systemCore, err = domain.Load_2(name); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		err = fmt.Errorf("there was an error loading System.Core:\n%s", err)
+		return
+	}
+	defer systemCore.Release()
+	if b.expressionType, err = systemCore.GetType_2("System.Linq.Expressions.Expression"); err != nil {
+		return
+	}
+	if b.lambdaType, err = systemCore.GetType_2("System.Linq.Expressions.LambdaExpression"); err != nil {
+		return
+	}
+
+	if b.objectType, err = mscorlibType(delegateType, "System.Object"); err != nil {
+		return
+	}
+	if b.bindingFlagsType, err = mscorlibType(delegateType, "System.Reflection.BindingFlags"); err != nil {
+		return
+	}
+	if b.binderType, err = mscorlibType(delegateType, "System.Reflection.Binder"); err != nil {
+		return
+	}
+	if b.getType, err = b.objectType.GetMethod("GetType", BindingFlags_Public|BindingFlags_Instance); err != nil {
+		return
+	}
+
+	typeType, err := mscorlibType(delegateType, "System.Type")
+	if err != nil {
+		return
+	}
+	defer typeType.Release()
+	methods, err := typeType.GetMethods(BindingFlags_Public | BindingFlags_Instance)
+	if err != nil {
+		return
+	}
+	for _, method := range methods {
+		if b.invokeMember == nil {
+			name, _ := method.GetName()
+			count, _ := method.GetParameterCount()
+			if name == "InvokeMember" && count == 5 {
+				b.invokeMember = method
+				continue
+			}
+		}
+		method.Release()
+	}
+	if b.invokeMember == nil {
+		err = fmt.Errorf("the Type.InvokeMember(String, BindingFlags, Binder, Object, Object[]) method was not found")
+	}
+	return
+}
+
+// call invokes a static factory method of Expression and returns the created node. The binder resolves the overload
+// from the types of the arguments and collects the trailing arguments of a params array into an array of its type
+func (b *expressionBuilder) call(method string, args ...any) (*IUnknown, error) {
+	result, err := b.expressionType.InvokeMember(method, BindingFlags_InvokeMethod|BindingFlags_Public|BindingFlags_Static, nil, args...)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error calling Expression.%s with (%s):\n%s", method, argTypes(args), err)
+	}
+	node, ok := result.(*IUnknown)
+	if !ok || node == nil {
+		releaseInterfaces(result)
+		return nil, fmt.Errorf("Expression.%s returned a %T instead of an expression", method, result)
+	}
+	b.owned = append(b.owned, node)
+	return node, nil
+}
+
+// parameterTypes returns the types of the parameters of a method, they are released with the builder
+func (b *expressionBuilder) parameterTypes(method *MethodInfo) ([]*IUnknown, error) {
+	psa, err := method.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	parameters, err := interfacesFromSafeArray[IUnknown](psa)
+	if err != nil {
+		return nil, err
+	}
+	var types []*IUnknown
+	for _, parameter := range parameters {
+		if parameter == nil {
+			continue
+		}
+		parameterType, errP := parameter.getProperty("ParameterType")
+		parameter.Release()
+		if err != nil {
+			releaseInterfaces(parameterType)
+			continue
+		}
+		if errP != nil {
+			err = errP
+			continue
+		}
+		unknown, ok := parameterType.(*IUnknown)
+		if !ok || unknown == nil {
+			releaseInterfaces(parameterType)
+			err = fmt.Errorf("ParameterInfo.ParameterType returned a %T", parameterType)
+			continue
+		}
+		b.owned = append(b.owned, unknown)
+		types = append(types, unknown)
+	}
+	return types, err
+}
+
+// release releases the types, methods and nodes held by the builder
+func (b *expressionBuilder) release() {
+	for _, t := range []*Type{b.expressionType, b.lambdaType, b.objectType, b.bindingFlagsType, b.binderType} {
+		if t != nil {
+			t.Release()
+		}
+	}
+	for _, m := range []*MethodInfo{b.getType, b.invokeMember} {
+		if m != nil {
+			m.Release()
+		}
+	}
+	for _, node := range b.owned {
+		node.Release()
+	}
+	*b = expressionBuilder{}
+}
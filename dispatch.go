@@ -0,0 +1,420 @@
+package clr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+const (
+	// DISPID_VALUE is the dispatch identifier of an object's default member
+	DISPID_VALUE int32 = 0
+	// DISPID_UNKNOWN is returned by GetIDsOfNames for a name that could not be resolved
+	DISPID_UNKNOWN int32 = -1
+	// DISPID_PROPERTYPUT is the named argument identifier of the value being assigned by a property put
+	DISPID_PROPERTYPUT int32 = -3
+)
+
+const (
+	// DISPATCH_METHOD the member is invoked as a method
+	DISPATCH_METHOD uint16 = 0x1
+	// DISPATCH_PROPERTYGET the member is retrieved as a property or data member
+	DISPATCH_PROPERTYGET uint16 = 0x2
+	// DISPATCH_PROPERTYPUT the member is changed as a property or data member
+	DISPATCH_PROPERTYPUT uint16 = 0x4
+	// DISPATCH_PROPERTYPUTREF the member is changed by a reference assignment
+	DISPATCH_PROPERTYPUTREF uint16 = 0x8
+)
+
+// IDispatchVtbl is the virtual function table of the COM IDispatch interface, which exposes an object's methods
+// and properties to late-bound callers such as managed code using reflection or the dynamic keyword
+// https://docs.microsoft.com/en-us/windows/win32/api/oaidl/nn-oaidl-idispatch
+type IDispatchVtbl struct {
+	QueryInterface uintptr
+	AddRef         uintptr
+	Release        uintptr
+	// GetTypeInfoCount Retrieves the number of type information interfaces that an object provides (either 0 or 1).
+	GetTypeInfoCount uintptr
+	// GetTypeInfo Retrieves the type information for an object
+	GetTypeInfo uintptr
+	// GetIDsOfNames Maps a single member and an optional set of argument names to a corresponding set of integer DISPIDs
+	GetIDsOfNames uintptr
+	// Invoke Provides access to properties and methods exposed by an object
+	Invoke uintptr
+}
+
+// DispatchError is returned when a call through a DispatchTable can not be completed.
+// HRESULT is the code that is reported back to the COM caller
+type DispatchError struct {
+	HRESULT uint32
+	Member  string
+	Err     error
+}
+
+func (e *DispatchError) Error() string {
+	if e.Member == "" {
+		return fmt.Sprintf("IDispatch call failed with HRESULT 0x%x: %s", e.HRESULT, e.Err)
+	}
+	return fmt.Sprintf("IDispatch call to %s failed with HRESULT 0x%x: %s", e.Member, e.HRESULT, e.Err)
+}
+
+func (e *DispatchError) Unwrap() error {
+	return e.Err
+}
+
+// dispatchMethod is a single exported Go method reachable through IDispatch
+type dispatchMethod struct {
+	name string
+	fn   reflect.Value
+}
+
+// DispatchTable maps IDispatch member names and DISPIDs to the exported methods of a Go value.
+// Every exported method is assigned a DISPID starting at 1, in the lexical order of the method names.
+// Member names are matched case-insensitively, as COM late binding does.
+type DispatchTable struct {
+	names   map[string]int32
+	methods map[int32]dispatchMethod
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	ratType  = reflect.TypeOf((*big.Rat)(nil))
+)
+
+// NewDispatchTable builds a DispatchTable from the exported methods of receiver.
+// Methods may return nothing, a single value, an error, or a single value followed by an error.
+func NewDispatchTable(receiver any) (*DispatchTable, error) {
+	if receiver == nil {
+		return nil, fmt.Errorf("a nil receiver can not be exposed through IDispatch")
+	}
+	v := reflect.ValueOf(receiver)
+	t := v.Type()
+	table := &DispatchTable{
+		names:   make(map[string]int32, t.NumMethod()),
+		methods: make(map[int32]dispatchMethod, t.NumMethod()),
+	}
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		ft := m.Type
+		results := ft.NumOut()
+		if results > 0 && ft.Out(results-1) == errorType {
+			results--
+		}
+		if results > 1 {
+			return nil, fmt.Errorf("the %s method returns %d values but at most one value and an error are supported", m.Name, ft.NumOut())
+		}
+		key := strings.ToLower(m.Name)
+		if _, ok := table.names[key]; ok {
+			return nil, fmt.Errorf("the %s method collides with another method when matched case-insensitively", m.Name)
+		}
+		dispID := int32(i + 1)
+		table.names[key] = dispID
+		table.methods[dispID] = dispatchMethod{name: m.Name, fn: v.Method(i)}
+	}
+	return table, nil
+}
+
+// IDsOfNames returns the DISPID for each name. Unknown names are reported as DISPID_UNKNOWN together with a
+// DispatchError carrying DISP_E_UNKNOWNNAME
+func (t *DispatchTable) IDsOfNames(names ...string) ([]int32, error) {
+	var err error
+	ids := make([]int32, len(names))
+	for i, name := range names {
+		id, ok := t.names[strings.ToLower(name)]
+		if !ok {
+			id = DISPID_UNKNOWN
+			if err == nil {
+				err = &DispatchError{HRESULT: DISP_E_UNKNOWNNAME, Member: name, Err: errors.New("unknown member name")}
+			}
+		}
+		ids[i] = id
+	}
+	return ids, err
+}
+
+// Name returns the Go method name that dispID is mapped to
+func (t *DispatchTable) Name(dispID int32) (string, bool) {
+	m, ok := t.methods[dispID]
+	return m.name, ok
+}
+
+// Call invokes the method identified by dispID with args, converting each argument to the method's parameter
+// type. A panic in the method is recovered and returned as a DISP_E_EXCEPTION DispatchError
+func (t *DispatchTable) Call(dispID int32, args []any) (result any, err error) {
+	m, ok := t.methods[dispID]
+	if !ok {
+		return nil, &DispatchError{HRESULT: DISP_E_MEMBERNOTFOUND, Err: fmt.Errorf("no member has DISPID %d", dispID)}
+	}
+
+	ft := m.fn.Type()
+	in, err := dispatchArgs(ft, args)
+	if err != nil {
+		err.(*DispatchError).Member = m.name
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			result = nil
+			err = &DispatchError{HRESULT: DISP_E_EXCEPTION, Member: m.name, Err: fmt.Errorf("panic: %v", r)}
+		}
+	}()
+
+	var out []reflect.Value
+	if ft.IsVariadic() {
+		out = m.fn.CallSlice(in)
+	} else {
+		out = m.fn.Call(in)
+	}
+	if len(out) > 0 && ft.Out(len(out)-1) == errorType {
+		if e := out[len(out)-1].Interface(); e != nil {
+			return nil, &DispatchError{HRESULT: DISP_E_EXCEPTION, Member: m.name, Err: e.(error)}
+		}
+		out = out[:len(out)-1]
+	}
+	if len(out) == 1 {
+		result = out[0].Interface()
+	}
+	return result, nil
+}
+
+// checkDispatchFlags returns the HRESULT of an IDispatch::Invoke call with the wFlags and number of named arguments,
+// S_OK if it can be dispatched. The members of a DispatchTable are methods: property gets and puts are not found,
+// unless the caller also allows a method call as late binding callers often do, and no argument can be named
+func checkDispatchFlags(wFlags uint16, cNamedArgs uint32) uint32 {
+	if wFlags&DISPATCH_METHOD == 0 {
+		return DISP_E_MEMBERNOTFOUND
+	}
+	if cNamedArgs > 0 {
+		return DISP_E_NONAMEDARGS
+	}
+	return S_OK
+}
+
+// dispatchArgs validates the number of arguments and converts them to the parameter types of ft.
+// The arguments of a variadic method are collected into a single slice argument
+func dispatchArgs(ft reflect.Type, args []any) ([]reflect.Value, error) {
+	fixed := ft.NumIn()
+	if ft.IsVariadic() {
+		fixed--
+	}
+	if len(args) < fixed || (!ft.IsVariadic() && len(args) > fixed) {
+		return nil, &DispatchError{HRESULT: DISP_E_BADPARAMCOUNT, Err: fmt.Errorf("expected %d arguments but received %d", fixed, len(args))}
+	}
+
+	in := make([]reflect.Value, 0, ft.NumIn())
+	for i := 0; i < fixed; i++ {
+		v, err := convertDispatchArg(args[i], ft.In(i))
+		if err != nil {
+			return nil, &DispatchError{HRESULT: DISP_E_TYPEMISMATCH, Err: fmt.Errorf("argument %d: %w", i, err)}
+		}
+		in = append(in, v)
+	}
+	if ft.IsVariadic() {
+		st := ft.In(fixed)
+		rest := reflect.MakeSlice(st, 0, len(args)-fixed)
+		for i := fixed; i < len(args); i++ {
+			v, err := convertDispatchArg(args[i], st.Elem())
+			if err != nil {
+				return nil, &DispatchError{HRESULT: DISP_E_TYPEMISMATCH, Err: fmt.Errorf("argument %d: %w", i, err)}
+			}
+			rest = reflect.Append(rest, v)
+		}
+		in = append(in, rest)
+	}
+	return in, nil
+}
+
+// convertDispatchArg converts a value received from a COM caller to the Go type t. Numbers are converted between
+// kinds as long as the value fits and slices are converted element by element
+func convertDispatchArg(arg any, t reflect.Type) (reflect.Value, error) {
+	if arg == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Slice, reflect.Map, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("can not convert a null value to %s", t)
+	}
+
+	v := reflect.ValueOf(arg)
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+
+	// Dates, currencies and decimals are converted exactly to the standard library types
+	switch x := arg.(type) {
+	case Date:
+		if t == timeType {
+			tm, err := x.Time()
+			return reflect.ValueOf(tm), err
+		}
+	case Currency:
+		if t == ratType {
+			return reflect.ValueOf(x.Rat()), nil
+		}
+	case Decimal:
+		if t == ratType {
+			return reflect.ValueOf(x.Rat()), nil
+		}
+	}
+
+	switch {
+	case isIntKind(v.Kind()) && isIntKind(t.Kind()):
+		c := reflect.New(t).Elem()
+		if isSignedKind(v.Kind()) {
+			n := v.Int()
+			if (isSignedKind(t.Kind()) && c.OverflowInt(n)) || (!isSignedKind(t.Kind()) && (n < 0 || c.OverflowUint(uint64(n)))) {
+				return reflect.Value{}, fmt.Errorf("%d overflows %s", n, t)
+			}
+		} else {
+			n := v.Uint()
+			if (isSignedKind(t.Kind()) && (n > 1<<63-1 || c.OverflowInt(int64(n)))) || (!isSignedKind(t.Kind()) && c.OverflowUint(n)) {
+				return reflect.Value{}, fmt.Errorf("%d overflows %s", n, t)
+			}
+		}
+		return v.Convert(t), nil
+	case isNumberKind(v.Kind()) && isFloatKind(t.Kind()):
+		return v.Convert(t), nil
+	case v.Kind() == reflect.Slice && t.Kind() == reflect.Slice:
+		s := reflect.MakeSlice(t, v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			e, err := convertDispatchArg(v.Index(i).Interface(), t.Elem())
+			if err != nil {
+				return reflect.Value{}, fmt.Errorf("element %d: %w", i, err)
+			}
+			s.Index(i).Set(e)
+		}
+		return s, nil
+	}
+	return reflect.Value{}, fmt.Errorf("can not convert %T to %s", arg, t)
+}
+
+// isNumberKind reports whether k is an integer or a float kind. uintptr holds addresses rather than numbers and is
+// excluded, as it is by the other kind helpers
+func isNumberKind(k reflect.Kind) bool {
+	return isIntKind(k) || isFloatKind(k)
+}
+
+func isIntKind(k reflect.Kind) bool {
+	return isSignedKind(k) || (k >= reflect.Uint && k <= reflect.Uint64)
+}
+
+func isSignedKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
+
+// DispatchObject exposes the exported methods of a Go value to managed code as a COM object implementing IUnknown
+// and IDispatch. Managed code sees it as a System.__ComObject and calls its methods by name through late binding
+// (Type.InvokeMember, VB late binding or the C# dynamic keyword), which lets it call back into Go for logging sinks,
+// resolve handlers or progress reporting.
+//
+// The object is pinned and kept alive for as long as its reference count is above zero. NewDispatchObject returns
+// an object holding one reference that belongs to the caller and must be given up with Release.
+type DispatchObject struct {
+	// vtbl must be the first field so a pointer to the DispatchObject is a valid COM interface pointer
+	vtbl   *IDispatchVtbl
+	refs   int32
+	table  *DispatchTable
+	pinner runtime.Pinner
+}
+
+// dispatchVtbl is shared by every DispatchObject and is populated with native callbacks on platforms that support them
+var dispatchVtbl IDispatchVtbl
+
+// liveDispatchObjects holds a reference to every DispatchObject with a non-zero reference count so the garbage
+// collector does not free objects that are only referenced from native code
+var liveDispatchObjects = struct {
+	sync.Mutex
+	objects map[*DispatchObject]struct{}
+}{objects: make(map[*DispatchObject]struct{})}
+
+// liveDispatchObject returns the DispatchObject at p when p points to one that is still referenced
+func liveDispatchObject(p unsafe.Pointer) (*DispatchObject, bool) {
+	obj := (*DispatchObject)(p)
+	liveDispatchObjects.Lock()
+	_, ok := liveDispatchObjects.objects[obj]
+	liveDispatchObjects.Unlock()
+	return obj, ok
+}
+
+// NewDispatchObject wraps the exported methods of receiver in a COM callable object with a reference count of one
+func NewDispatchObject(receiver any) (*DispatchObject, error) {
+	table, err := NewDispatchTable(receiver)
+	if err != nil {
+		return nil, err
+	}
+	obj := &DispatchObject{
+		vtbl:  &dispatchVtbl,
+		refs:  1,
+		table: table,
+	}
+	obj.pinner.Pin(obj)
+
+	liveDispatchObjects.Lock()
+	liveDispatchObjects.objects[obj] = struct{}{}
+	liveDispatchObjects.Unlock()
+	return obj, nil
+}
+
+// Variant returns a VT_DISPATCH Variant that holds a new reference to the object so it can be passed to managed code
+func (obj *DispatchObject) Variant() Variant {
+	v, _ := ToVariant(obj)
+	return v
+}
+
+// Table returns the DispatchTable used to resolve calls made to the object
+func (obj *DispatchObject) Table() *DispatchTable {
+	return obj.table
+}
+
+// AddRef increments the reference count of the object and returns the new count.
+// A released object can not be revived and always returns 0
+func (obj *DispatchObject) AddRef() uint32 {
+	for {
+		refs := atomic.LoadInt32(&obj.refs)
+		if refs <= 0 {
+			return 0
+		}
+		if atomic.CompareAndSwapInt32(&obj.refs, refs, refs+1) {
+			return uint32(refs + 1)
+		}
+	}
+}
+
+// Release decrements the reference count of the object and returns the new count.
+// When the count reaches zero the object is unpinned and can be garbage collected
+func (obj *DispatchObject) Release() uint32 {
+	for {
+		refs := atomic.LoadInt32(&obj.refs)
+		if refs <= 0 {
+			return 0
+		}
+		if atomic.CompareAndSwapInt32(&obj.refs, refs, refs-1) {
+			if refs == 1 {
+				liveDispatchObjects.Lock()
+				delete(liveDispatchObjects.objects, obj)
+				liveDispatchObjects.Unlock()
+				obj.pinner.Unpin()
+			}
+			return uint32(refs - 1)
+		}
+	}
+}
+
+// RefCount returns the current reference count of the object
+func (obj *DispatchObject) RefCount() uint32 {
+	return uint32(atomic.LoadInt32(&obj.refs))
+}
@@ -0,0 +1,270 @@
+package clr
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+// dispatchReceiver is exposed through IDispatch by the tests
+type dispatchReceiver struct {
+	calls int
+}
+
+func (r *dispatchReceiver) Add(a, b int) int {
+	r.calls++
+	return a + b
+}
+
+func (r *dispatchReceiver) Echo(value any) any {
+	return value
+}
+
+func (r *dispatchReceiver) Fail() error {
+	return errors.New("the job failed")
+}
+
+func (r *dispatchReceiver) Join(sep string, parts ...string) string {
+	return strings.Join(parts, sep)
+}
+
+func (r *dispatchReceiver) Lines(lines []string) int {
+	return len(lines)
+}
+
+func (r *dispatchReceiver) Narrow(b int8) int8 {
+	return b
+}
+
+func (r *dispatchReceiver) Panic() {
+	panic("unexpected")
+}
+
+func (r *dispatchReceiver) Price(amount *big.Rat) string {
+	if amount == nil {
+		return "none"
+	}
+	return amount.FloatString(2)
+}
+
+func (r *dispatchReceiver) Scale(f float64) float64 {
+	return f * 2
+}
+
+func (r *dispatchReceiver) Year(t time.Time) int {
+	return t.Year()
+}
+
+func (r *dispatchReceiver) unexported() {}
+
+type collidingReceiver struct{}
+
+func (collidingReceiver) Run()  {}
+func (collidingReceiver) RUN()  {}
+func (collidingReceiver) Stop() {}
+
+type multipleResultsReceiver struct{}
+
+func (multipleResultsReceiver) Pair() (int, int) { return 0, 0 }
+
+func TestNewDispatchTable(t *testing.T) {
+	table, err := NewDispatchTable(&dispatchReceiver{})
+	if err != nil {
+		t.Fatalf("NewDispatchTable returned an error: %s", err)
+	}
+	// DISPIDs start at 1 in the lexical order of the exported methods
+	names := []string{"Add", "Echo", "Fail", "Join", "Lines", "Narrow", "Panic", "Price", "Scale", "Year"}
+	for i, name := range names {
+		dispID := int32(i + 1)
+		if got, ok := table.Name(dispID); !ok || got != name {
+			t.Errorf("Name(%d) = %q, %t, want %q", dispID, got, ok, name)
+		}
+		for _, variant := range []string{name, strings.ToLower(name), strings.ToUpper(name)} {
+			ids, err := table.IDsOfNames(variant)
+			if err != nil || len(ids) != 1 || ids[0] != dispID {
+				t.Errorf("IDsOfNames(%q) = %v, %v, want %d", variant, ids, err, dispID)
+			}
+		}
+	}
+	if name, ok := table.Name(int32(len(names) + 1)); ok {
+		t.Errorf("Name(%d) = %q for a DISPID without a method", len(names)+1, name)
+	}
+	if name, ok := table.Name(DISPID_VALUE); ok {
+		t.Errorf("Name(DISPID_VALUE) = %q", name)
+	}
+
+	ids, err := table.IDsOfNames("Add", "unexported", "Missing")
+	if want := []int32{1, DISPID_UNKNOWN, DISPID_UNKNOWN}; fmt.Sprint(ids) != fmt.Sprint(want) {
+		t.Errorf("IDsOfNames returned %v, want %v", ids, want)
+	}
+	var dispErr *DispatchError
+	if !errors.As(err, &dispErr) || dispErr.HRESULT != DISP_E_UNKNOWNNAME || dispErr.Member != "unexported" {
+		t.Errorf("IDsOfNames of unknown names returned the error %v", err)
+	}
+}
+
+func TestNewDispatchTableInvalid(t *testing.T) {
+	for _, receiver := range []any{nil, collidingReceiver{}, multipleResultsReceiver{}} {
+		if _, err := NewDispatchTable(receiver); err == nil {
+			t.Errorf("NewDispatchTable(%T) did not return an error", receiver)
+		}
+	}
+}
+
+func TestDispatchTableCall(t *testing.T) {
+	receiver := &dispatchReceiver{}
+	table, err := NewDispatchTable(receiver)
+	if err != nil {
+		t.Fatalf("NewDispatchTable returned an error: %s", err)
+	}
+	amount, _ := NewDecimal("12.345")
+	tests := []struct {
+		method string
+		args   []any
+		want   any
+	}{
+		{"Add", []any{int32(2), int64(3)}, 5},
+		{"Add", []any{uint8(2), int16(-3)}, -1},
+		{"Echo", []any{nil}, nil},
+		{"Echo", []any{"value"}, "value"},
+		{"Join", []any{","}, ""},
+		{"Join", []any{",", "a", "b", "c"}, "a,b,c"},
+		{"Lines", []any{[]any{"a", "b"}}, 2},
+		{"Lines", []any{nil}, 0},
+		{"Narrow", []any{int32(-128)}, int8(-128)},
+		{"Price", []any{amount}, "12.35"},
+		{"Price", []any{Currency(15000)}, "1.50"},
+		{"Price", []any{nil}, "none"},
+		{"Scale", []any{int32(3)}, 6.0},
+		{"Scale", []any{float32(1.5)}, 3.0},
+		{"Year", []any{Date(-36522)}, 1800},
+	}
+	for _, tt := range tests {
+		ids, _ := table.IDsOfNames(tt.method)
+		got, err := table.Call(ids[0], tt.args)
+		if err != nil {
+			t.Errorf("%s(%v) returned an error: %s", tt.method, tt.args, err)
+			continue
+		}
+		if fmt.Sprintf("%#v", got) != fmt.Sprintf("%#v", tt.want) {
+			t.Errorf("%s(%v) = %#v, want %#v", tt.method, tt.args, got, tt.want)
+		}
+	}
+	if receiver.calls != 2 {
+		t.Errorf("the receiver was called %d times, want 2", receiver.calls)
+	}
+}
+
+func TestDispatchTableCallErrors(t *testing.T) {
+	table, err := NewDispatchTable(&dispatchReceiver{})
+	if err != nil {
+		t.Fatalf("NewDispatchTable returned an error: %s", err)
+	}
+	tests := []struct {
+		method  string
+		dispID  int32
+		args    []any
+		hresult uint32
+	}{
+		{"Add", 0, []any{int32(1)}, DISP_E_BADPARAMCOUNT},
+		{"Add", 0, []any{int32(1), int32(2), int32(3)}, DISP_E_BADPARAMCOUNT},
+		{"Join", 0, nil, DISP_E_BADPARAMCOUNT},
+		{"Add", 0, []any{"1", int32(2)}, DISP_E_TYPEMISMATCH},
+		{"Add", 0, []any{nil, int32(2)}, DISP_E_TYPEMISMATCH},
+		{"Add", 0, []any{1.5, int32(2)}, DISP_E_TYPEMISMATCH},
+		{"Add", 0, []any{uintptr(1), int32(2)}, DISP_E_TYPEMISMATCH},
+		{"Scale", 0, []any{uintptr(1)}, DISP_E_TYPEMISMATCH},
+		{"Narrow", 0, []any{int32(128)}, DISP_E_TYPEMISMATCH},
+		{"Narrow", 0, []any{uint64(1 << 63)}, DISP_E_TYPEMISMATCH},
+		{"Join", 0, []any{",", "a", int32(1)}, DISP_E_TYPEMISMATCH},
+		{"Lines", 0, []any{[]any{"a", int32(1)}}, DISP_E_TYPEMISMATCH},
+		{"Year", 0, []any{Date(maxOADate)}, DISP_E_TYPEMISMATCH},
+		{"Fail", 0, nil, DISP_E_EXCEPTION},
+		{"Panic", 0, nil, DISP_E_EXCEPTION},
+		{"", 42, nil, DISP_E_MEMBERNOTFOUND},
+	}
+	for _, tt := range tests {
+		dispID := tt.dispID
+		if tt.method != "" {
+			ids, _ := table.IDsOfNames(tt.method)
+			dispID = ids[0]
+		}
+		result, err := table.Call(dispID, tt.args)
+		var dispErr *DispatchError
+		if !errors.As(err, &dispErr) || dispErr.HRESULT != tt.hresult {
+			t.Errorf("%s(%v) returned %v and the error %v, want the HRESULT 0x%x", tt.method, tt.args, result, err, tt.hresult)
+			continue
+		}
+		if dispErr.Member != tt.method {
+			t.Errorf("the error of %s(%v) is reported for the %q member", tt.method, tt.args, dispErr.Member)
+		}
+	}
+
+	ids, _ := table.IDsOfNames("Fail")
+	if _, err := table.Call(ids[0], nil); err == nil || !strings.Contains(err.Error(), "the job failed") {
+		t.Errorf("the error returned by Fail is not wrapped: %v", err)
+	}
+}
+
+func TestCheckDispatchFlags(t *testing.T) {
+	tests := []struct {
+		flags      uint16
+		namedArgs  uint32
+		wantResult uint32
+	}{
+		{DISPATCH_METHOD, 0, S_OK},
+		{DISPATCH_METHOD | DISPATCH_PROPERTYGET, 0, S_OK},
+		{DISPATCH_PROPERTYGET, 0, DISP_E_MEMBERNOTFOUND},
+		{DISPATCH_PROPERTYPUT, 1, DISP_E_MEMBERNOTFOUND},
+		{DISPATCH_PROPERTYPUTREF, 1, DISP_E_MEMBERNOTFOUND},
+		{0, 0, DISP_E_MEMBERNOTFOUND},
+		{DISPATCH_METHOD, 1, DISP_E_NONAMEDARGS},
+	}
+	for _, tt := range tests {
+		if got := checkDispatchFlags(tt.flags, tt.namedArgs); got != tt.wantResult {
+			t.Errorf("checkDispatchFlags(0x%x, %d) = 0x%x, want 0x%x", tt.flags, tt.namedArgs, got, tt.wantResult)
+		}
+	}
+}
+
+func TestDispatchObjectReferences(t *testing.T) {
+	obj, err := NewDispatchObject(&dispatchReceiver{})
+	if err != nil {
+		t.Fatalf("NewDispatchObject returned an error: %s", err)
+	}
+	if _, ok := liveDispatchObject(unsafe.Pointer(obj)); !ok || obj.RefCount() != 1 {
+		t.Fatalf("a new object has %d references and is live: %t", obj.RefCount(), ok)
+	}
+	if refs := obj.AddRef(); refs != 2 {
+		t.Errorf("AddRef returned %d, want 2", refs)
+	}
+
+	v := obj.Variant()
+	if v.VT != VT_DISPATCH || v.ptr() != unsafe.Pointer(obj) || obj.RefCount() != 3 {
+		t.Errorf("the VARIANT has the type 0x%x and the object %d references", v.VT, obj.RefCount())
+	}
+	v.Clear()
+
+	if refs := obj.Release(); refs != 1 {
+		t.Errorf("Release returned %d, want 1", refs)
+	}
+	if _, ok := liveDispatchObject(unsafe.Pointer(obj)); !ok {
+		t.Errorf("the object is not live while it has a reference")
+	}
+	if refs := obj.Release(); refs != 0 {
+		t.Errorf("the last Release returned %d, want 0", refs)
+	}
+	if _, ok := liveDispatchObject(unsafe.Pointer(obj)); ok {
+		t.Errorf("the object is still live without references")
+	}
+	if refs := obj.AddRef(); refs != 0 {
+		t.Errorf("AddRef revived a released object with %d references", refs)
+	}
+	if refs := obj.Release(); refs != 0 || obj.RefCount() != 0 {
+		t.Errorf("Release of a released object returned %d", refs)
+	}
+}
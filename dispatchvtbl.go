@@ -0,0 +1,152 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+func init() {
+	dispatchVtbl = IDispatchVtbl{
+		QueryInterface:   syscall.NewCallback(dispatchQueryInterface),
+		AddRef:           syscall.NewCallback(dispatchAddRef),
+		Release:          syscall.NewCallback(dispatchRelease),
+		GetTypeInfoCount: syscall.NewCallback(dispatchGetTypeInfoCount),
+		GetTypeInfo:      syscall.NewCallback(dispatchGetTypeInfo),
+		GetIDsOfNames:    syscall.NewCallback(dispatchGetIDsOfNames),
+		Invoke:           syscall.NewCallback(dispatchInvoke),
+	}
+}
+
+// dispatchQueryInterface implements IUnknown::QueryInterface, only IUnknown and IDispatch are supported
+func dispatchQueryInterface(obj *DispatchObject, riid *windows.GUID, ppvObject *unsafe.Pointer) uintptr {
+	if ppvObject == nil {
+		return uintptr(E_POINTER)
+	}
+	if *riid != IID_IUnknown && *riid != IID_IDispatch {
+		*ppvObject = nil
+		return uintptr(E_NOINTERFACE)
+	}
+	obj.AddRef()
+	*ppvObject = unsafe.Pointer(obj)
+	return S_OK
+}
+
+// dispatchAddRef implements IUnknown::AddRef
+func dispatchAddRef(obj *DispatchObject) uintptr {
+	return uintptr(obj.AddRef())
+}
+
+// dispatchRelease implements IUnknown::Release
+func dispatchRelease(obj *DispatchObject) uintptr {
+	return uintptr(obj.Release())
+}
+
+// dispatchGetTypeInfoCount implements IDispatch::GetTypeInfoCount, no type information is provided
+func dispatchGetTypeInfoCount(obj *DispatchObject, pctinfo *uint32) uintptr {
+	if pctinfo == nil {
+		return uintptr(E_POINTER)
+	}
+	*pctinfo = 0
+	return S_OK
+}
+
+// dispatchGetTypeInfo implements IDispatch::GetTypeInfo
+func dispatchGetTypeInfo(obj *DispatchObject, iTInfo, lcid uintptr, ppTInfo *unsafe.Pointer) uintptr {
+	if ppTInfo != nil {
+		*ppTInfo = nil
+	}
+	return uintptr(E_NOTIMPL)
+}
+
+// dispatchGetIDsOfNames implements IDispatch::GetIDsOfNames
+//
+//	HRESULT GetIDsOfNames(
+//	  REFIID   riid,
+//	  LPOLESTR *rgszNames,
+//	  UINT     cNames,
+//	  LCID     lcid,
+//	  DISPID   *rgDispId
+//	);
+func dispatchGetIDsOfNames(obj *DispatchObject, riid *windows.GUID, rgszNames **uint16, cNames, lcid uintptr, rgDispId *int32) uintptr {
+	if rgszNames == nil || rgDispId == nil {
+		return uintptr(E_POINTER)
+	}
+	names := make([]string, cNames)
+	for i, p := range unsafe.Slice(rgszNames, cNames) {
+		names[i] = windows.UTF16PtrToString(p)
+	}
+	ids, err := obj.table.IDsOfNames(names...)
+	copy(unsafe.Slice(rgDispId, cNames), ids)
+	if err != nil {
+		return uintptr(DISP_E_UNKNOWNNAME)
+	}
+	return S_OK
+}
+
+// dispatchInvoke implements IDispatch::Invoke by converting the arguments and calling the mapped Go method
+//
+//	HRESULT Invoke(
+//	  DISPID     dispIdMember,
+//	  REFIID     riid,
+//	  LCID       lcid,
+//	  WORD       wFlags,
+//	  DISPPARAMS *pDispParams,
+//	  VARIANT    *pVarResult,
+//	  EXCEPINFO  *pExcepInfo,
+//	  UINT       *puArgErr
+//	);
+func dispatchInvoke(obj *DispatchObject, dispIdMember uintptr, riid *windows.GUID, lcid, wFlags uintptr, pDispParams *DispParams, pVarResult *Variant, pExcepInfo *ExcepInfo, puArgErr *uint32) uintptr {
+	var cNamedArgs uint32
+	if pDispParams != nil {
+		cNamedArgs = pDispParams.cNamedArgs
+	}
+	if hr := checkDispatchFlags(uint16(wFlags), cNamedArgs); hr != S_OK {
+		return uintptr(hr)
+	}
+
+	var args []any
+	if pDispParams != nil {
+		if pDispParams.cArgs > 0 {
+			rgvarg := unsafe.Slice(pDispParams.rgvarg, pDispParams.cArgs)
+			args = make([]any, pDispParams.cArgs)
+			// Arguments are stored from last to first
+			for i := range rgvarg {
+				arg, err := FromVariant(&rgvarg[len(rgvarg)-1-i])
+				if err != nil {
+					if puArgErr != nil {
+						*puArgErr = uint32(len(rgvarg) - 1 - i)
+					}
+					return uintptr(DISP_E_TYPEMISMATCH)
+				}
+				args[i] = arg
+			}
+		}
+	}
+
+	result, err := obj.table.Call(int32(dispIdMember), args)
+	if err != nil {
+		var dispErr *DispatchError
+		if !errors.As(err, &dispErr) || dispErr.HRESULT == DISP_E_EXCEPTION {
+			if pExcepInfo != nil {
+				*pExcepInfo = ExcepInfo{scode: E_FAIL}
+				pExcepInfo.bstrSource, _ = SysAllocString("go-clr")
+				pExcepInfo.bstrDescription, _ = SysAllocString(err.Error())
+			}
+			return uintptr(DISP_E_EXCEPTION)
+		}
+		return uintptr(dispErr.HRESULT)
+	}
+
+	if pVarResult != nil {
+		if *pVarResult, err = ToVariant(result); err != nil {
+			return uintptr(DISP_E_TYPEMISMATCH)
+		}
+	}
+	return S_OK
+}
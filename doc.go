@@ -0,0 +1,9 @@
+// Package clr is a PoC package that wraps Windows syscalls necessary to load and the CLR into the current process and
+// execute a managed DLL from disk or a managed EXE from memory
+//
+// Hosting the CLR is only possible on Windows. On every other platform the package still compiles so importers
+// build everywhere: the platform independent pieces (VARIANT and SAFEARRAY layouts, HRESULTs, GUIDs, argument
+// building, output buffering and image inspection) work as usual and the functions that need the CLR return
+// ErrUnsupportedPlatform. The SAFEARRAY, BSTR and VARIANT functions are backed by an in-process emulator of
+// OleAut32.dll with the same memory layouts.
+package clr
@@ -0,0 +1,227 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DomainManager runs jobs in application domains created for them with ICORRuntimeHost.CreateDomain, so the
+// assemblies a job loads and the static state it leaves behind are freed when its domain is unloaded instead of
+// accumulating in the default domain. Without a DomainPool every job gets a fresh domain that is unloaded when it
+// finishes. A DomainManager can be used by several goroutines at once, each job runs in its own domain, but the
+// entry points invoked by Run are serialized as the output is collected from the process wide STDOUT and STDERR
+type DomainManager struct {
+	runtimeHost *ICORRuntimeHost
+	name        string
+	pool        *DomainPool
+
+	mu sync.Mutex
+	// created is the number of domains created, used to name them
+	created int
+	// active is the number of jobs running
+	active int
+	idle   []*managedDomain
+	closed bool
+}
+
+// managedDomain is a domain created by a DomainManager
+type managedDomain struct {
+	domain  *AppDomain
+	name    string
+	created time.Time
+	jobs    int
+}
+
+// NewDomainManager returns a DomainManager creating its domains with runtimeHost. The domains are named after name
+// with a sequence number, such as "job-1", and reused according to pool, which can be nil to unload each domain after
+// its job. The manager takes a reference on runtimeHost until it is closed
+func NewDomainManager(runtimeHost *ICORRuntimeHost, name string, pool *DomainPool) (*DomainManager, error) {
+	debugPrint("Entering into domainmanager.NewDomainManager()...")
+	if runtimeHost == nil {
+		return nil, fmt.Errorf("the runtime host is null")
+	}
+	if name == "" {
+		name = "job"
+	}
+	if pool != nil {
+		if err := pool.validate(); err != nil {
+			return nil, err
+		}
+		copied := *pool
+		pool = &copied
+	}
+	runtimeHost.AddRef()
+	return &DomainManager{runtimeHost: runtimeHost, name: name, pool: pool}, nil
+}
+
+// Do runs job with an application domain of the manager, a new one or one reused from the pool. The domain must not
+// be released by job and the objects obtained from it can't be used once job returns, as the domain may be unloaded
+func (m *DomainManager) Do(job func(domain *AppDomain) error) error {
+	debugPrint("Entering into domainmanager.Do()...")
+	d, err := m.acquire()
+	if err != nil {
+		return err
+	}
+	err = job(d.domain)
+	if errU := m.put(d); errU != nil && err == nil {
+		err = errU
+	}
+	return err
+}
+
+// Run loads the executable assembly rawBytes into an application domain of the manager and invokes its entry point
+// with params, as InvokeAssembly does, and returns the error of the invocation along with the output collected so far.
+// The output of the job is collected when STDOUT and STDERR are redirected with RedirectStdoutStderr, it holds the
+// lock on them from the invocation of the entry point until the output is read so concurrent Run calls don't read
+// each other's output. The output written meanwhile by a job run with Do, or by a managed thread that outlives its
+// job, is read by the Run call collecting it next
+func (m *DomainManager) Run(rawBytes []byte, params []string) (result JobResult, err error) {
+	debugPrint("Entering into domainmanager.Run()...")
+	result.ExitCode = -1
+	err = m.Do(func(domain *AppDomain) error {
+		result.Domain, _ = domain.GetFriendlyName()
+		assembly, errL := domain.load(rawBytes)
+		if errL != nil {
+			return errL
+		}
+		defer assembly.Release()
+		methodInfo, errE := assembly.GetEntryPoint()
+		if errE != nil {
+			return errE
+		}
+		defer methodInfo.Release()
+
+		var ret any
+		var errI error
+		result.Stdout, result.Stderr, ret, errI = invokeEntryPoint(methodInfo, params)
+		if errI != nil {
+			return errI
+		}
+		// The objects of the domain can't outlive it
+		if code, errC := exitCode(ret); errC == nil {
+			result.ExitCode = code
+		} else {
+			releaseInterfaces(ret)
+		}
+		return nil
+	})
+	return
+}
+
+// Close unloads the idle domains of the pool and releases the runtime host. The domains running a job are unloaded
+// when it finishes
+func (m *DomainManager) Close() (err error) {
+	debugPrint("Entering into domainmanager.Close()...")
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return fmt.Errorf("the domain manager is already closed")
+	}
+	m.closed = true
+	idle := m.idle
+	m.idle = nil
+	// Unloading the pool holds the runtime host like a job
+	m.active++
+	m.mu.Unlock()
+
+	for _, d := range idle {
+		if errU := m.unload(d); errU != nil && err == nil {
+			err = errU
+		}
+	}
+	m.done()
+	return
+}
+
+// acquire returns an idle domain of the pool that is still young enough, or creates a new domain
+func (m *DomainManager) acquire() (*managedDomain, error) {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("the domain manager is closed")
+	}
+	var expired []*managedDomain
+	var d *managedDomain
+	for len(m.idle) > 0 && d == nil {
+		d = m.idle[len(m.idle)-1]
+		m.idle = m.idle[:len(m.idle)-1]
+		if m.expired(d) {
+			expired = append(expired, d)
+			d = nil
+		}
+	}
+	if d == nil {
+		m.created++
+		d = &managedDomain{name: fmt.Sprintf("%s-%d", m.name, m.created)}
+	}
+	m.active++
+	m.mu.Unlock()
+
+	for _, e := range expired {
+		if err := m.unload(e); err != nil {
+			debugPrint(err.Error())
+		}
+	}
+	if d.domain != nil {
+		return d, nil
+	}
+	domain, err := m.runtimeHost.CreateDomain(d.name)
+	if err != nil {
+		m.done()
+		return nil, fmt.Errorf("there was an error creating the %s application domain:\n%s", d.name, err)
+	}
+	d.domain, d.created = domain, time.Now()
+	return d, nil
+}
+
+// put returns a domain whose job finished to the pool, or unloads it if it reached a limit of the pool
+func (m *DomainManager) put(d *managedDomain) (err error) {
+	d.jobs++
+	m.mu.Lock()
+	pooled := !m.closed && m.pool != nil && !m.expired(d) && len(m.idle) < m.pool.MaxIdle
+	if pooled {
+		m.idle = append(m.idle, d)
+	}
+	m.mu.Unlock()
+	if !pooled {
+		err = m.unload(d)
+	}
+	m.done()
+	return
+}
+
+// done ends a job, or the unloading of the pool by Close, and releases the runtime host if it was the last one of a
+// closed manager
+func (m *DomainManager) done() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.active--
+	if m.closed && m.active == 0 && m.runtimeHost != nil {
+		m.runtimeHost.Release()
+		m.runtimeHost = nil
+	}
+}
+
+// expired reports whether a domain reached the number of jobs or the age after which it is not reused
+func (m *DomainManager) expired(d *managedDomain) bool {
+	if m.pool == nil {
+		return true
+	}
+	return (m.pool.MaxJobs > 0 && d.jobs >= m.pool.MaxJobs) || (m.pool.MaxAge > 0 && time.Since(d.created) >= m.pool.MaxAge)
+}
+
+// unload unloads a domain and releases it
+func (m *DomainManager) unload(d *managedDomain) error {
+	debugPrint(fmt.Sprintf("Unloading the %s application domain...", d.name))
+	err := m.runtimeHost.UnloadDomain(d.domain)
+	d.domain.Release()
+	d.domain = nil
+	if err != nil {
+		return fmt.Errorf("there was an error unloading the %s application domain:\n%s", d.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package clr
+
+import (
+	"fmt"
+	"time"
+)
+
+// DomainPool configures a DomainManager to keep the domains of finished jobs and reuse them for the next ones, which
+// saves creating a domain and loading its dependencies for every job. A domain is unloaded once it reaches one of
+// the limits
+type DomainPool struct {
+	// MaxIdle is the number of idle domains kept for reuse, the domains finishing a job while there are as many idle
+	// ones are unloaded
+	MaxIdle int
+	// MaxJobs is the number of jobs run in a domain before it is unloaded, 0 for no limit
+	MaxJobs int
+	// MaxAge is the time after its creation a domain stops being reused and is unloaded, 0 for no limit
+	MaxAge time.Duration
+}
+
+// validate checks that the limits of the pool are not negative
+func (p *DomainPool) validate() error {
+	if p.MaxIdle < 0 || p.MaxJobs < 0 || p.MaxAge < 0 {
+		return fmt.Errorf("the limits of the domain pool can not be negative: %+v", *p)
+	}
+	return nil
+}
+
+// JobResult is the outcome of an assembly run by a DomainManager
+type JobResult struct {
+	// Domain is the friendly name of the application domain the job ran in
+	Domain string
+	// Stdout and Stderr are the output of the job, read when STDOUT and STDERR are redirected with
+	// RedirectStdoutStderr. Stderr also holds the exception thrown by the entry point
+	Stdout string
+	Stderr string
+	// ExitCode is the value returned by the entry point, 0 when it returns void and -1 when Run returns an error
+	ExitCode int32
+}
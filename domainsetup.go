@@ -0,0 +1,43 @@
+package clr
+
+// LoaderOptimization is the policy used to share the code of the assemblies between application domains
+//
+//	enum LoaderOptimization
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.loaderoptimization?view=netframework-4.8
+type LoaderOptimization int32
+
+const (
+	// LoaderOptimization_NotSpecified lets the CLR or the host choose the policy
+	LoaderOptimization_NotSpecified LoaderOptimization = 0
+	// LoaderOptimization_SingleDomain doesn't share the code, the application has a single domain
+	LoaderOptimization_SingleDomain LoaderOptimization = 1
+	// LoaderOptimization_MultiDomain shares the code of all the assemblies between the domains
+	LoaderOptimization_MultiDomain LoaderOptimization = 2
+	// LoaderOptimization_MultiDomainHost only shares the code of the strong-named assemblies of the GAC
+	LoaderOptimization_MultiDomainHost LoaderOptimization = 3
+)
+
+// DomainSetup configures an application domain created with ICORRuntimeHost.CreateDomainWithSetup. The empty fields
+// keep the defaults of the AppDomainSetup class
+// https://docs.microsoft.com/en-us/dotnet/api/system.appdomainsetup?view=netframework-4.8
+type DomainSetup struct {
+	// ApplicationBase is the directory the CLR probes for the assemblies of the domain, the directory of the host
+	// executable by default
+	ApplicationBase string
+	// ConfigurationFile is the path of the configuration file of the domain, holding settings such as binding
+	// redirects and the supported runtimes
+	ConfigurationFile string
+	// ConfigurationBytes is the content of the configuration file of the domain, used instead of ConfigurationFile so
+	// the configuration of a tool loaded from memory doesn't have to be written to disk
+	ConfigurationBytes []byte
+	// PrivateBinPath is the list of directories under ApplicationBase also probed for assemblies, separated by
+	// semicolons
+	PrivateBinPath string
+	// ShadowCopyFiles loads copies of the assemblies so the original files aren't locked while the domain runs
+	ShadowCopyFiles bool
+	// LoaderOptimization is the policy used to share the code of the assemblies with the other domains
+	LoaderOptimization LoaderOptimization
+	// DisallowBindingRedirects ignores the binding redirects of the configuration file
+	DisallowBindingRedirects bool
+}
@@ -0,0 +1,55 @@
+package clr
+
+// ManagedException describes a managed exception, read when it is reported to Go
+type ManagedException struct {
+	// Type is the full name of the type of the exception, such as "System.InvalidOperationException"
+	Type string
+	// Message is the message of the exception
+	Message string
+	// StackTrace is the managed stack trace of the exception, empty if it was not thrown
+	StackTrace string
+	// Details is the text returned by the ToString method of the exception, which includes the inner exceptions
+	Details string
+}
+
+// DomainEvent is raised by the ProcessExit and DomainUnload events of an application domain
+type DomainEvent struct {
+	// FriendlyName is the friendly name of the domain that raised the event
+	FriendlyName string
+}
+
+// UnhandledExceptionEvent is raised when an exception is not caught by managed code, such as one thrown by a
+// background thread
+type UnhandledExceptionEvent struct {
+	// Exception is the exception that was not caught. The Type of an object thrown by a language that can throw
+	// objects that are not exceptions is the type of the object
+	Exception ManagedException
+	// IsTerminating is true when the CLR terminates the process once the handlers return
+	IsTerminating bool
+}
+
+// AssemblyLoadEvent is raised when an assembly is loaded into an application domain
+type AssemblyLoadEvent struct {
+	// Name is the identity of the loaded assembly
+	Name AssemblyName
+	// FullName is the display name of the loaded assembly
+	FullName string
+	// Location is the path of the loaded assembly, empty if it was loaded from memory
+	Location string
+}
+
+// ResolveEvent is raised when the CLR can't find a type or a manifest resource
+type ResolveEvent struct {
+	// Name is the name of the type or resource
+	Name string
+	// RequestingAssembly is the display name of the assembly that requested it, empty if it is unknown
+	RequestingAssembly string
+}
+
+// ToChannel returns an event handler that sends the events to ch. The managed thread raising an event waits until it
+// is received, so ch should be buffered to not hold it
+func ToChannel[E any](ch chan<- E) func(E) {
+	return func(event E) {
+		ch <- event
+	}
+}
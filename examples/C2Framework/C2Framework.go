@@ -81,7 +81,7 @@ func main() {
 	if *verbose {
 		fmt.Println("[-] Executing Rubeus...")
 	}
-	stdout, stderr := clr.InvokeAssembly(methodInfo, []string{"klist"})
+	stdout, stderr, _ := clr.InvokeAssembly(methodInfo, []string{"klist"})
 	if *debug {
 		fmt.Printf("[DEBUG] Returned STDOUT/STDERR\nSTDOUT: %s\nSTDERR: %s\n", stdout, stderr)
 	}
@@ -98,7 +98,7 @@ func main() {
 	if *verbose {
 		fmt.Println("[-] Executing the Rubeus x2...")
 	}
-	stdout, stderr = clr.InvokeAssembly(methodInfo, []string{"triage", "/service:KRBTGT"})
+	stdout, stderr, _ = clr.InvokeAssembly(methodInfo, []string{"triage", "/service:KRBTGT"})
 	if *debug {
 		fmt.Printf("[DEBUG] Returned STDOUT/STDERR\nSTDOUT: %s\nSTDERR: %s\n", stdout, stderr)
 	}
@@ -133,7 +133,7 @@ func main() {
 	if *verbose {
 		fmt.Println("[-] Executing Seatbelt...")
 	}
-	stdout, stderr = clr.InvokeAssembly(seatBelt, []string{"AntiVirus"})
+	stdout, stderr, _ = clr.InvokeAssembly(seatBelt, []string{"AntiVirus"})
 	if *debug {
 		fmt.Printf("[DEBUG] Returned STDOUT/STDERR\nSTDOUT: %s\nSTDERR: %s\n", stdout, stderr)
 	}
@@ -150,7 +150,7 @@ func main() {
 	if *verbose {
 		fmt.Println("[-] Executing Seatbelt x2...")
 	}
-	stdout, stderr = clr.InvokeAssembly(seatBelt, []string{"DotNet"})
+	stdout, stderr, _ = clr.InvokeAssembly(seatBelt, []string{"DotNet"})
 	if *debug {
 		fmt.Printf("[DEBUG] Returned STDOUT/STDERR\nSTDOUT: %s\nSTDERR: %s\n", stdout, stderr)
 	}
@@ -185,7 +185,7 @@ func main() {
 	if *verbose {
 		fmt.Println("[-] Executing SharpUp...")
 	}
-	stdout, stderr = clr.InvokeAssembly(sharpUp, []string{"audit"})
+	stdout, stderr, _ = clr.InvokeAssembly(sharpUp, []string{"audit"})
 	if *debug {
 		fmt.Printf("[DEBUG] Returned STDOUT/STDERR\nSTDOUT: %s\nSTDERR: %s\n", stdout, stderr)
 	}
@@ -8,7 +8,6 @@ import (
 	"log"
 	"os"
 	"runtime"
-	"strings"
 	"syscall"
 	"unsafe"
 
@@ -92,13 +91,12 @@ func main() {
 	fmt.Printf("[+] Executable entrypoint found at 0x%x\n", uintptr(unsafe.Pointer(methodInfo)))
 
 	var paramSafeArray *clr.SafeArray
-	methodSignature, err := methodInfo.GetString()
-	if err != nil {
-		return
-	}
+	signature, err := methodInfo.Signature()
+	must(err)
+	fmt.Printf("[+] Entrypoint signature: %s\n", signature)
 
 	fmt.Println("[+] Checking if the assembly requires arguments...")
-	if !strings.Contains(methodSignature, "Void Main()") {
+	if len(signature.Parameters) > 0 {
 		if len(params) < 1 {
 			log.Fatal("the assembly requires arguments but none were provided\nUsage: EXEfromMemory.exe <exe_file> <exe_args>")
 		}
@@ -112,8 +110,12 @@ func main() {
 		Val: uintptr(0),
 	}
 	fmt.Println("[+] Invoking...")
-	err = methodInfo.Invoke_3(nullVariant, paramSafeArray)
+	result, err := methodInfo.Invoke_3(nullVariant, paramSafeArray)
+	must(err)
+	ret, err := clr.FromVariant(&result)
 	must(err)
+	fmt.Printf("[+] Return value: %v\n", ret)
+	must(result.Clear())
 
 	appDomain.Release()
 	runtimeHost.(*clr.ICORRuntimeHost).Release()
@@ -1,8 +1,6 @@
 //go:build windows
 // +build windows
 
-// Package clr is a PoC package that wraps Windows syscalls necessary to load and the CLR into the current process and
-// execute a managed DLL from disk or a managed EXE from memory
 package clr
 
 import (
@@ -122,7 +120,8 @@ func ExecuteDLLFromDisk(targetRuntime, dllpath, typeName, methodName, argument s
 
 // ExecuteByteArray is a wrapper function that will automatically loads the supplied target framework into the current
 // process using the legacy APIs, then load and execute an executable from memory. If no targetRuntime is specified, it
-// will default to latest. It takes in a byte array of the executable to load and run and returns the return code.
+// will default to latest. It takes in a byte array of the executable to load and run and returns the value returned by
+// its entry point, 0 when the entry point returns void.
 // You can supply an array of strings as command line arguments.
 func ExecuteByteArray(targetRuntime string, rawBytes []byte, params []string) (retCode int32, err error) {
 	retCode = -1
@@ -183,12 +182,12 @@ func ExecuteByteArray(targetRuntime string, rawBytes []byte, params []string) (r
 	}
 
 	var paramSafeArray *SafeArray
-	methodSignature, err := methodInfo.GetString()
+	paramCount, err := methodInfo.GetParameterCount()
 	if err != nil {
 		return
 	}
 
-	if expectsParams(methodSignature) {
+	if paramCount > 0 {
 		if paramSafeArray, err = PrepareParameters(params); err != nil {
 			return
 		}
@@ -198,15 +197,101 @@ func ExecuteByteArray(targetRuntime string, rawBytes []byte, params []string) (r
 		VT:  1,
 		Val: uintptr(0),
 	}
-	err = methodInfo.Invoke_3(nullVariant, paramSafeArray)
+	result, err := methodInfo.Invoke_3(nullVariant, paramSafeArray)
 	if err != nil {
 		return
 	}
+	ret, err := takeVariant(&result)
+	if err != nil {
+		return
+	}
+	if retCode, err = exitCode(ret); err != nil {
+		return
+	}
 	appDomain.Release()
 	runtimeHost.Release()
 	runtimeInfo.Release()
 	metahost.Release()
-	return 0, nil
+	return retCode, nil
+}
+
+// InvokeWithArgs invokes a method with arguments of any type supported by ToVariant: numbers, bools, strings, []byte
+// as byte[], []string as string[], nested slices as arrays of arrays, nil as a null reference, Variants and the
+// managed objects previously obtained from the CLR. The arguments are checked against the method's Signature and
+// bound with Signature.Bind before the call, which converts numbers to the parameter types and fills in default values
+// and params arrays, then passed in a SAFEARRAY of VARIANTs. this is the instance the method is invoked on and nil for
+// static methods. The result is the method's return value converted with FromVariant, nil for void
+// methods. An *IUnknown result holds a reference that must be released by the caller
+func InvokeWithArgs(method *MethodInfo, this any, args ...any) (result any, err error) {
+	debugPrint("Entering into go-clr.InvokeWithArgs()...")
+	signature, err := method.Signature()
+	if err != nil {
+		return
+	}
+	if args, err = signature.Bind(args); err != nil {
+		return
+	}
+
+	parameters, err := SafeArrayFromSlice(args)
+	if err != nil {
+		return
+	}
+	defer parameters.Destroy()
+
+	obj, err := ToVariant(this)
+	if err != nil {
+		return
+	}
+	// A Variant is copied as is and still belongs to the caller
+	if _, borrowed := this.(Variant); !borrowed {
+		defer obj.Clear()
+	}
+
+	ret, err := method.Invoke_3(obj, parameters.SafeArray())
+	if err != nil {
+		return
+	}
+	return takeVariant(&ret)
+}
+
+// InvokeStatic calls the public static method methodName of the type typeName, such as "Namespace.Type", in an
+// assembly loaded from memory with LoadLibraryAssembly or AppDomain.Load_3. The arguments are converted with ToVariant
+// and the overload is resolved by the CLR from their types: an int32 matches an int parameter and widens to a long or
+// double one, a string matches a string or object one and nil matches any reference type. The result is converted as
+// in InvokeWithArgs
+func InvokeStatic(assembly *Assembly, typeName, methodName string, args ...any) (result any, err error) {
+	debugPrint("Entering into go-clr.InvokeStatic()...")
+	staticType, err := assembly.GetType_2(typeName)
+	if err != nil {
+		return
+	}
+	defer staticType.Release()
+
+	result, err = staticType.InvokeMember(methodName, BindingFlags_InvokeMethod|BindingFlags_Public|BindingFlags_Static, nil, args...)
+	if err != nil {
+		err = fmt.Errorf("there was an error calling %s.%s(%s):\n%s", typeName, methodName, argTypes(args), err)
+	}
+	return
+}
+
+// LoadLibraryAssembly uses a previously instantiated runtimehost to load an assembly, such as a library DLL without
+// an entry point, into the default AppDomain. The returned Assembly can be used with InvokeStatic for the duration of
+// the program and must be released by the caller
+func LoadLibraryAssembly(runtimeHost *ICORRuntimeHost, rawBytes []byte) (assembly *Assembly, err error) {
+	debugPrint("Entering into go-clr.LoadLibraryAssembly()...")
+	appDomain, err := GetAppDomain(runtimeHost)
+	if err != nil {
+		return
+	}
+	defer appDomain.Release()
+
+	safeArrayPtr, err := CreateSafeArray(rawBytes)
+	if err != nil {
+		return
+	}
+	defer SafeArrayDestroy(safeArrayPtr)
+
+	return appDomain.Load_3(safeArrayPtr)
 }
 
 // LoadCLR loads the target runtime into the current process and returns the runtimehost
@@ -255,8 +340,10 @@ func LoadCLR(targetRuntime string) (runtimeHost *ICORRuntimeHost, err error) {
 
 // ExecuteByteArrayDefaultDomain uses a previously instantiated runtimehost, gets the default AppDomain,
 // loads the assembly into, executes the assembly, and then releases AppDomain
-// Intended to be used by C2 frameworks to quickly execute an assembly one time
-func ExecuteByteArrayDefaultDomain(runtimeHost *ICORRuntimeHost, rawBytes []byte, params []string) (stdout string, stderr string) {
+// Intended to be used by C2 frameworks to quickly execute an assembly one time. The return code is the value returned
+// by the assembly's entry point, 0 when it returns void and -1 when an error is reported on stderr
+func ExecuteByteArrayDefaultDomain(runtimeHost *ICORRuntimeHost, rawBytes []byte, params []string) (stdout string, stderr string, retCode int32) {
+	retCode = -1
 	appDomain, err := GetAppDomain(runtimeHost)
 	if err != nil {
 		stderr = err.Error()
@@ -281,13 +368,13 @@ func ExecuteByteArrayDefaultDomain(runtimeHost *ICORRuntimeHost, rawBytes []byte
 	}
 
 	var paramSafeArray *SafeArray
-	methodSignature, err := methodInfo.GetString()
+	paramCount, err := methodInfo.GetParameterCount()
 	if err != nil {
 		stderr = err.Error()
 		return
 	}
 
-	if expectsParams(methodSignature) {
+	if paramCount > 0 {
 		if paramSafeArray, err = PrepareParameters(params); err != nil {
 			stderr = err.Error()
 			return
@@ -299,11 +386,20 @@ func ExecuteByteArrayDefaultDomain(runtimeHost *ICORRuntimeHost, rawBytes []byte
 		Val: uintptr(0),
 	}
 
-	err = methodInfo.Invoke_3(nullVariant, paramSafeArray)
+	result, err := methodInfo.Invoke_3(nullVariant, paramSafeArray)
+	if err != nil {
+		stderr = err.Error()
+		return
+	}
+	ret, err := takeVariant(&result)
 	if err != nil {
 		stderr = err.Error()
 		return
 	}
+	if retCode, err = exitCode(ret); err != nil {
+		stderr = err.Error()
+		return
+	}
 
 	assembly.Release()
 	appDomain.Release()
@@ -332,19 +428,28 @@ func LoadAssembly(runtimeHost *ICORRuntimeHost, rawBytes []byte) (methodInfo *Me
 
 // InvokeAssembly uses the MethodInfo structure of a previously loaded assembly and executes it.
 // The intended purpose is for the assembly to be executed many times throughout the duration of the
-// program. Commonly used with C2 frameworks
-func InvokeAssembly(methodInfo *MethodInfo, params []string) (stdout string, stderr string) {
+// program. Commonly used with C2 frameworks. The result is the method's return value converted with FromVariant, an
+// int32 for an entry point declared as int Main and nil for void methods. An *IUnknown result holds a reference
+// that must be released by the caller
+func InvokeAssembly(methodInfo *MethodInfo, params []string) (stdout string, stderr string, result any) {
+	stdout, stderr, result, _ = invokeEntryPoint(methodInfo, params)
+	return
+}
+
+// invokeEntryPoint invokes an entry point as InvokeAssembly does and also returns the error of the invocation, which
+// is reported on stderr too
+func invokeEntryPoint(methodInfo *MethodInfo, params []string) (stdout string, stderr string, result any, invokeErr error) {
 	var paramSafeArray *SafeArray
-	methodSignature, err := methodInfo.GetString()
+	paramCount, err := methodInfo.GetParameterCount()
 	if err != nil {
 		stderr = err.Error()
-		return
+		return stdout, stderr, nil, err
 	}
 
-	if expectsParams(methodSignature) {
+	if paramCount > 0 {
 		if paramSafeArray, err = PrepareParameters(params); err != nil {
 			stderr = err.Error()
-			return
+			return stdout, stderr, nil, err
 		}
 	}
 
@@ -359,10 +464,14 @@ func InvokeAssembly(methodInfo *MethodInfo, params []string) (stdout string, std
 	mutex.Lock()
 	defer mutex.Unlock()
 
-	err = methodInfo.Invoke_3(nullVariant, paramSafeArray)
+	ret, err := methodInfo.Invoke_3(nullVariant, paramSafeArray)
 	if err != nil {
 		stderr = err.Error()
+		invokeErr = err
 		// Don't return because there could be data on STDOUT/STDERR
+	} else if result, err = takeVariant(&ret); err != nil {
+		stderr = err.Error()
+		invokeErr = err
 	}
 
 	// Read data from previously redirected STDOUT/STDERR
@@ -2,7 +2,4 @@ module github.com/tobiasja/go-clr
 
 go 1.23
 
-require (
-	golang.org/x/sys v0.27.0
-	golang.org/x/text v0.20.0
-)
+require golang.org/x/sys v0.27.0
@@ -0,0 +1,168 @@
+package clr
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GUID is a platform independent globally unique identifier with the same layout as the Windows GUID structure
+//
+//	typedef struct _GUID {
+//	  unsigned long  Data1;
+//	  unsigned short Data2;
+//	  unsigned short Data3;
+//	  unsigned char  Data4[8];
+//	} GUID;
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/guiddef/ns-guiddef-guid
+type GUID struct {
+	Data1 uint32
+	Data2 uint16
+	Data3 uint16
+	Data4 [8]byte
+}
+
+// ParseGUID parses a GUID in registry format, {xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx}.
+// The surrounding braces are optional and hexadecimal digits are case-insensitive
+func ParseGUID(s string) (GUID, error) {
+	var g GUID
+	str := s
+	if strings.HasPrefix(str, "{") && strings.HasSuffix(str, "}") {
+		str = str[1 : len(str)-1]
+	}
+	if len(str) != 36 || str[8] != '-' || str[13] != '-' || str[18] != '-' || str[23] != '-' {
+		return g, fmt.Errorf("%q is not a GUID in registry format", s)
+	}
+	var b [16]byte
+	if _, err := hex.Decode(b[:], []byte(str[0:8]+str[9:13]+str[14:18]+str[19:23]+str[24:])); err != nil {
+		return g, fmt.Errorf("%q is not a GUID in registry format: %s", s, err)
+	}
+	g.Data1 = binary.BigEndian.Uint32(b[0:4])
+	g.Data2 = binary.BigEndian.Uint16(b[4:6])
+	g.Data3 = binary.BigEndian.Uint16(b[6:8])
+	copy(g.Data4[:], b[8:])
+	return g, nil
+}
+
+// MustParseGUID is like ParseGUID but panics if the string can not be parsed
+func MustParseGUID(s string) GUID {
+	g, err := ParseGUID(s)
+	if err != nil {
+		panic(err)
+	}
+	return g
+}
+
+// String returns the GUID in upper case registry format, {xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx}
+func (g GUID) String() string {
+	return fmt.Sprintf("{%08X-%04X-%04X-%02X-%02X}", g.Data1, g.Data2, g.Data3, g.Data4[:2], g.Data4[2:])
+}
+
+// IsNull reports whether g is GUID_NULL, {00000000-0000-0000-0000-000000000000}
+func (g GUID) IsNull() bool {
+	return g == GUID{}
+}
+
+// Bytes returns the 16-byte wire form of the GUID as it is laid out in memory on Windows and in the metadata
+// #GUID heap. Data1, Data2 and Data3 are little-endian while Data4 is kept in order
+func (g GUID) Bytes() [16]byte {
+	var b [16]byte
+	binary.LittleEndian.PutUint32(b[0:4], g.Data1)
+	binary.LittleEndian.PutUint16(b[4:6], g.Data2)
+	binary.LittleEndian.PutUint16(b[6:8], g.Data3)
+	copy(b[8:], g.Data4[:])
+	return b
+}
+
+// GUIDFromBytes reads a GUID from its 16-byte wire form
+func GUIDFromBytes(b []byte) (GUID, error) {
+	var g GUID
+	if len(b) != 16 {
+		return g, fmt.Errorf("a GUID is 16 bytes but %d bytes were provided", len(b))
+	}
+	g.Data1 = binary.LittleEndian.Uint32(b[0:4])
+	g.Data2 = binary.LittleEndian.Uint16(b[4:6])
+	g.Data3 = binary.LittleEndian.Uint16(b[6:8])
+	copy(g.Data4[:], b[8:])
+	return g, nil
+}
+
+// GUIDFromHeap returns the GUID at index in a metadata #GUID heap. The heap is an array of 16-byte GUIDs that is
+// indexed from 1, index 0 is the null GUID
+// ECMA-335 II.24.2.5 #GUID heap
+func GUIDFromHeap(heap []byte, index uint32) (GUID, error) {
+	if index == 0 {
+		return GUID{}, nil
+	}
+	offset := (uint64(index) - 1) * 16
+	if offset+16 > uint64(len(heap)) {
+		return GUID{}, fmt.Errorf("GUID heap index %d is out of range for a heap of %d bytes", index, len(heap))
+	}
+	return GUIDFromBytes(heap[offset : offset+16])
+}
+
+// AppendGUIDHeap appends g to a metadata #GUID heap and returns the new heap and the 1-based index of g
+func AppendGUIDHeap(heap []byte, g GUID) ([]byte, uint32) {
+	b := g.Bytes()
+	heap = append(heap, b[:]...)
+	return heap, uint32(len(heap) / 16)
+}
+
+// knownGUIDs are the class and interface identifiers used to host the CLR, keyed by their conventional names
+var knownGUIDs = map[string]GUID{
+	"CLSID_CLRMetaHost":     MustParseGUID("{9280188D-0E8E-4867-B30C-7FA83884E8DE}"),
+	"CLSID_CLRRuntimeHost":  MustParseGUID("{90F1A06E-7712-4762-86B5-7A5EBA6BDB02}"),
+	"CLSID_CorRuntimeHost":  MustParseGUID("{CB2F6723-AB3A-11D2-9C40-00C04FA30A3E}"),
+	"IID_ICLRMetaHost":      MustParseGUID("{D332DB9E-B9B3-4125-8207-A14884F53216}"),
+	"IID_ICLRRuntimeInfo":   MustParseGUID("{BD39D1D2-BA2F-486A-89B0-B4B0CB466891}"),
+	"IID_ICLRRuntimeHost":   MustParseGUID("{90F1A06C-7712-4762-86B5-7A5EBA6BDB02}"),
+	"IID_ICorRuntimeHost":   MustParseGUID("{CB2F6722-AB3A-11D2-9C40-00C04FA30A3E}"),
+	"IID_IAppDomainSetup":   MustParseGUID("{27FFF232-A7A8-40DD-8D4A-734AD59FCD41}"),
+	"IID_AppDomain":         MustParseGUID("{05F696DC-2B29-3663-AD8B-C4389CF2A713}"),
+	"IID__Object":           MustParseGUID("{65074F7F-63C0-304E-AF0A-D51741CB4A8D}"),
+	"IID__Type":             MustParseGUID("{BCA8B44D-AAD6-3A86-8AB7-03349F4F2DA2}"),
+	"IID__Assembly":         MustParseGUID("{17156360-2F1A-384A-BC52-FDE93C215C5B}"),
+	"IID__MethodInfo":       MustParseGUID("{FFCC1B5D-ECB8-38DD-9B01-3DC8ABC2AA5F}"),
+	"IID_IUnknown":          MustParseGUID("{00000000-0000-0000-C000-000000000046}"),
+	"IID_IDispatch":         MustParseGUID("{00020400-0000-0000-C000-000000000046}"),
+	"IID_IErrorInfo":        MustParseGUID("{1CF2B120-547D-101B-8E65-08002B2BD119}"),
+	"IID_ISupportErrorInfo": MustParseGUID("{DF0B3D60-548F-101B-8E65-08002B2BD119}"),
+}
+
+// KnownGUID returns a well-known CLR or COM class or interface identifier by name, e.g. "IID_ICLRMetaHost"
+func KnownGUID(name string) (GUID, bool) {
+	g, ok := knownGUIDs[name]
+	return g, ok
+}
+
+// KnownGUIDName returns the name of a well-known CLR or COM class or interface identifier
+func KnownGUIDName(g GUID) (string, bool) {
+	for name, known := range knownGUIDs {
+		if known == g {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// KnownGUIDNames returns the sorted names of every well-known identifier
+func KnownGUIDNames() []string {
+	names := make([]string, 0, len(knownGUIDs))
+	for name := range knownGUIDs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// mustKnownGUID returns a well-known identifier and panics when the name is not in the table
+func mustKnownGUID(name string) GUID {
+	g, ok := knownGUIDs[name]
+	if !ok {
+		panic(fmt.Sprintf("%s is not a known GUID", name))
+	}
+	return g
+}
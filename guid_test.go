@@ -0,0 +1,179 @@
+package clr
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestGUIDRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		str  string
+		guid GUID
+		wire [16]byte
+	}{
+		{
+			"IID_ICLRMetaHost",
+			"{D332DB9E-B9B3-4125-8207-A14884F53216}",
+			GUID{0xD332DB9E, 0xB9B3, 0x4125, [8]byte{0x82, 0x07, 0xA1, 0x48, 0x84, 0xF5, 0x32, 0x16}},
+			[16]byte{0x9E, 0xDB, 0x32, 0xD3, 0xB3, 0xB9, 0x25, 0x41, 0x82, 0x07, 0xA1, 0x48, 0x84, 0xF5, 0x32, 0x16},
+		},
+		{
+			"CLSID_CorRuntimeHost",
+			"{CB2F6723-AB3A-11D2-9C40-00C04FA30A3E}",
+			GUID{0xCB2F6723, 0xAB3A, 0x11D2, [8]byte{0x9C, 0x40, 0x00, 0xC0, 0x4F, 0xA3, 0x0A, 0x3E}},
+			[16]byte{0x23, 0x67, 0x2F, 0xCB, 0x3A, 0xAB, 0xD2, 0x11, 0x9C, 0x40, 0x00, 0xC0, 0x4F, 0xA3, 0x0A, 0x3E},
+		},
+		{
+			"IID_IDispatch",
+			"{00020400-0000-0000-C000-000000000046}",
+			GUID{0x00020400, 0, 0, [8]byte{0xC0, 0, 0, 0, 0, 0, 0, 0x46}},
+			[16]byte{0x00, 0x04, 0x02, 0x00, 0, 0, 0, 0, 0xC0, 0, 0, 0, 0, 0, 0, 0x46},
+		},
+	}
+	for _, tt := range tests {
+		g, err := ParseGUID(tt.str)
+		if err != nil {
+			t.Errorf("ParseGUID(%q) returned an error: %s", tt.str, err)
+			continue
+		}
+		if g != tt.guid {
+			t.Errorf("ParseGUID(%q) = %#v, want %#v", tt.str, g, tt.guid)
+		}
+		if known, ok := KnownGUID(tt.name); !ok || known != g {
+			t.Errorf("KnownGUID(%q) = %s, %t, want %s", tt.name, known, ok, g)
+		}
+		if name, ok := KnownGUIDName(g); !ok || name != tt.name {
+			t.Errorf("KnownGUIDName(%s) = %q, %t, want %q", g, name, ok, tt.name)
+		}
+		if s := g.String(); s != tt.str {
+			t.Errorf("String() = %q, want %q", s, tt.str)
+		}
+		// Data1, Data2 and Data3 are little-endian on the wire, Data4 is kept in order
+		wire := g.Bytes()
+		if wire != tt.wire {
+			t.Errorf("Bytes() of %s = % X, want % X", g, wire, tt.wire)
+		}
+		if back, err := GUIDFromBytes(wire[:]); err != nil || back != g {
+			t.Errorf("GUIDFromBytes(% X) = %s, %v, want %s", wire, back, err, g)
+		}
+	}
+}
+
+func TestParseGUIDFormats(t *testing.T) {
+	want := MustParseGUID("{D332DB9E-B9B3-4125-8207-A14884F53216}")
+	for _, s := range []string{
+		"D332DB9E-B9B3-4125-8207-A14884F53216",
+		"{d332db9e-b9b3-4125-8207-a14884f53216}",
+		"d332DB9e-B9b3-4125-8207-A14884f53216",
+	} {
+		if g, err := ParseGUID(s); err != nil || g != want {
+			t.Errorf("ParseGUID(%q) = %s, %v, want %s", s, g, err, want)
+		}
+	}
+	if g, err := ParseGUID("{00000000-0000-0000-0000-000000000000}"); err != nil || !g.IsNull() {
+		t.Errorf("ParseGUID of GUID_NULL = %s, %v", g, err)
+	}
+}
+
+func TestParseGUIDInvalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"{}",
+		// Missing or unbalanced braces
+		"{D332DB9E-B9B3-4125-8207-A14884F53216",
+		"D332DB9E-B9B3-4125-8207-A14884F53216}",
+		"(D332DB9E-B9B3-4125-8207-A14884F53216)",
+		"{{D332DB9E-B9B3-4125-8207-A14884F53216}}",
+		// Bad hexadecimal digits
+		"{G332DB9E-B9B3-4125-8207-A14884F53216}",
+		"{D332DB9E-B9B3-4125-8207-A14884F5321Z}",
+		"{+332DB9E-B9B3-4125-8207-A14884F53216}",
+		// Wrong group lengths or separators
+		"{D332DB9-EB9B3-4125-8207-A14884F53216}",
+		"{D332DB9EB-9B3-4125-8207-A14884F53216}",
+		"{D332DB9E-B9B3-4125-82070-A14884F5321}",
+		"{D332DB9E-B9B3-4125-8207-A14884F5321}",
+		"{D332DB9E-B9B3-4125-8207-A14884F532160}",
+		"{D332DB9E_B9B3_4125_8207_A14884F53216}",
+		"D332DB9EB9B341258207A14884F53216",
+	} {
+		if g, err := ParseGUID(s); err == nil {
+			t.Errorf("ParseGUID(%q) = %s, want an error", s, g)
+		}
+	}
+}
+
+func TestGUIDFromBytesLength(t *testing.T) {
+	for _, n := range []int{0, 15, 17} {
+		if g, err := GUIDFromBytes(make([]byte, n)); err == nil {
+			t.Errorf("GUIDFromBytes of %d bytes = %s, want an error", n, g)
+		}
+	}
+}
+
+func TestGUIDHeap(t *testing.T) {
+	first := MustParseGUID("{D332DB9E-B9B3-4125-8207-A14884F53216}")
+	second := MustParseGUID("{00020400-0000-0000-C000-000000000046}")
+	heap, index := AppendGUIDHeap(nil, first)
+	if index != 1 {
+		t.Errorf("the first GUID of the heap has the index %d, want 1", index)
+	}
+	heap, index = AppendGUIDHeap(heap, second)
+	if index != 2 || len(heap) != 32 {
+		t.Errorf("the second GUID has the index %d in a heap of %d bytes, want 2 and 32", index, len(heap))
+	}
+	wire := first.Bytes()
+	if !bytes.Equal(heap[:16], wire[:]) {
+		t.Errorf("the heap starts with % X, want % X", heap[:16], wire)
+	}
+
+	tests := []struct {
+		index   uint32
+		want    GUID
+		wantErr bool
+	}{
+		// Index 0 is the null GUID, even in an empty heap
+		{0, GUID{}, false},
+		{1, first, false},
+		{2, second, false},
+		{3, GUID{}, true},
+		{0xffffffff, GUID{}, true},
+	}
+	for _, tt := range tests {
+		g, err := GUIDFromHeap(heap, tt.index)
+		if (err != nil) != tt.wantErr || g != tt.want {
+			t.Errorf("GUIDFromHeap(%d) = %s, %v, want %s and an error: %t", tt.index, g, err, tt.want, tt.wantErr)
+		}
+	}
+	if g, err := GUIDFromHeap(nil, 0); err != nil || !g.IsNull() {
+		t.Errorf("GUIDFromHeap of index 0 in an empty heap = %s, %v", g, err)
+	}
+	if g, err := GUIDFromHeap(heap[:31], 2); err == nil {
+		t.Errorf("GUIDFromHeap of a truncated GUID = %s, want an error", g)
+	}
+}
+
+func TestKnownGUIDNames(t *testing.T) {
+	names := KnownGUIDNames()
+	if len(names) != len(knownGUIDs) {
+		t.Fatalf("KnownGUIDNames returned %d names for %d GUIDs", len(names), len(knownGUIDs))
+	}
+	seen := make(map[GUID]string)
+	for i, name := range names {
+		if i > 0 && names[i-1] >= name {
+			t.Errorf("the names are not sorted: %q before %q", names[i-1], name)
+		}
+		g, ok := KnownGUID(name)
+		if !ok || g.IsNull() {
+			t.Errorf("KnownGUID(%q) = %s, %t", name, g, ok)
+		}
+		if other, ok := seen[g]; ok {
+			t.Errorf("%q and %q are both %s", other, name, g)
+		}
+		seen[g] = name
+	}
+	if _, ok := KnownGUID("IID_Missing"); ok {
+		t.Errorf("KnownGUID found an unknown name")
+	}
+}
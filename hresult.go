@@ -9,14 +9,54 @@ const (
 	// COR_E_TARGETINVOCATION is TargetInvocationException
 	// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.targetinvocationexception?view=net-5.0
 	COR_E_TARGETINVOCATION uint32 = 0x80131604
+	// COR_E_REFLECTIONTYPELOAD is ReflectionTypeLoadException
+	// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.reflectiontypeloadexception?view=net-5.0
+	COR_E_REFLECTIONTYPELOAD uint32 = 0x80131602
 	// COR_E_SAFEARRAYRANKMISMATCH is SafeArrayRankMismatchException
 	COR_E_SAFEARRAYRANKMISMATCH uint32 = 0x80131538
 	// COR_E_BADIMAGEFORMAT is BadImageFormatException
 	COR_E_BADIMAGEFORMAT uint32 = 0x8007000b
+	// COR_E_NEWER_RUNTIME is the BadImageFormatException of an assembly built for a newer runtime
+	COR_E_NEWER_RUNTIME uint32 = 0x8013101b
+	// COR_E_ASSEMBLYEXPECTED is the BadImageFormatException of a module without an assembly manifest
+	COR_E_ASSEMBLYEXPECTED uint32 = 0x80131018
+	// COR_E_FILENOTFOUND is FileNotFoundException
+	// https://docs.microsoft.com/en-us/dotnet/api/system.io.filenotfoundexception?view=netframework-4.8
+	COR_E_FILENOTFOUND uint32 = 0x80070002
+	// COR_E_DIRECTORYNOTFOUND is DirectoryNotFoundException
+	COR_E_DIRECTORYNOTFOUND uint32 = 0x80070003
 	// DISP_E_BADPARAMCOUNT is invalid number of parameters
 	DISP_E_BADPARAMCOUNT uint32 = 0x8002000e
 	// E_POINTER Pointer that is not valid
 	E_POINTER uint32 = 0x80004003
 	// E_NOINTERFACE No such interface supported
 	E_NOINTERFACE uint32 = 0x80004002
+	// E_NOTIMPL Not implemented
+	E_NOTIMPL uint32 = 0x80004001
+	// E_FAIL Unspecified failure
+	E_FAIL uint32 = 0x80004005
+	// E_INVALIDARG One or more arguments are not valid
+	E_INVALIDARG uint32 = 0x80070057
+	// DISP_E_MEMBERNOTFOUND The requested member does not exist
+	DISP_E_MEMBERNOTFOUND uint32 = 0x80020003
+	// DISP_E_PARAMNOTFOUND One of the parameter DISPIDs does not correspond to a parameter on the method
+	DISP_E_PARAMNOTFOUND uint32 = 0x80020004
+	// DISP_E_TYPEMISMATCH One or more of the arguments could not be coerced
+	DISP_E_TYPEMISMATCH uint32 = 0x80020005
+	// DISP_E_UNKNOWNNAME The name is not known
+	DISP_E_UNKNOWNNAME uint32 = 0x80020006
+	// DISP_E_NONAMEDARGS This implementation of IDispatch does not support named arguments
+	DISP_E_NONAMEDARGS uint32 = 0x80020007
+	// DISP_E_EXCEPTION The application needs to raise an exception, EXCEPINFO is filled in
+	DISP_E_EXCEPTION uint32 = 0x80020009
+	// DISP_E_BADVARTYPE The VARIANT type is not valid
+	DISP_E_BADVARTYPE uint32 = 0x80020008
+	// DISP_E_BADINDEX An index is outside the bounds of the array
+	DISP_E_BADINDEX uint32 = 0x8002000b
+	// DISP_E_ARRAYISLOCKED The array is locked and can not be destroyed
+	DISP_E_ARRAYISLOCKED uint32 = 0x8002000d
+	// E_UNEXPECTED Unexpected failure
+	E_UNEXPECTED uint32 = 0x8000ffff
+	// E_OUTOFMEMORY Failed to allocate necessary memory
+	E_OUTOFMEMORY uint32 = 0x8007000e
 )
@@ -174,9 +174,12 @@ func (obj *ICORRuntimeHost) GetDefaultDomain() (IUnknown *IUnknown, err error) {
 // );
 // https://docs.microsoft.com/en-us/previous-versions/dotnet/netframework-4.0/ms164322(v=vs.100)
 func (obj *ICORRuntimeHost) CreateDomain(FriendlyName string) (pAppDomain *AppDomain, err error) {
-	pwzFriendlyName := &utf16Le(FriendlyName)[0]
-	var iu *IUnknown
 	debugPrint("Entering into icorruntimehost.CreateDomain()...")
+	pwzFriendlyName, err := friendlyName(FriendlyName)
+	if err != nil {
+		return
+	}
+	var iu *IUnknown
 	hr, _, err := syscall.SyscallN(
 		obj.vtbl.CreateDomain,
 		uintptr(unsafe.Pointer(obj)),
@@ -193,11 +196,140 @@ func (obj *ICORRuntimeHost) CreateDomain(FriendlyName string) (pAppDomain *AppDo
 		err = fmt.Errorf("the ICORRuntimeHost::CreateDomain method returned a non-zero HRESULT: 0x%x", hr)
 		return
 	}
+	defer iu.Release()
 
 	err = iu.QueryInterface(IID_AppDomain, unsafe.Pointer(&pAppDomain))
 	return
 }
 
+// CreateDomainEx Creates an application domain. This method allows the caller to pass an IAppDomainSetup instance to
+// configure additional features of the returned _AppDomain instance.
+// HRESULT CreateDomainEx (
+//
+//	[in] LPCWSTR    pwzFriendlyName,
+//	[in] IUnknown*  pSetup,
+//	[in] IUnknown*  pEvidence,
+//	[out] IUnknown** pAppDomain
+//
+// );
+// https://docs.microsoft.com/en-us/dotnet/framework/unmanaged-api/hosting/icorruntimehost-createdomainex-method
+func (obj *ICORRuntimeHost) CreateDomainEx(FriendlyName string, pSetup *IUnknown, pEvidence *IUnknown) (pAppDomain *AppDomain, err error) {
+	debugPrint("Entering into icorruntimehost.CreateDomainEx()...")
+	pwzFriendlyName, err := friendlyName(FriendlyName)
+	if err != nil {
+		return
+	}
+	var iu *IUnknown
+	hr, _, err := syscall.SyscallN(
+		obj.vtbl.CreateDomainEx,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(pwzFriendlyName)), // [in] LPCWSTR pwzFriendlyName - An optional parameter used to give a friendly name to the domain
+		uintptr(unsafe.Pointer(pSetup)),          // [in] IUnknown* pSetup - An optional interface pointer of type IAppDomainSetup, obtained with CreateDomainSetup
+		uintptr(unsafe.Pointer(pEvidence)),       // [in] IUnknown* pEvidence - An optional interface pointer of type IIdentity, obtained with CreateEvidence
+		uintptr(unsafe.Pointer(&iu)),             // [out] IUnknown** pAppDomain
+	)
+	if err != syscall.Errno(0) {
+		debugPrint(fmt.Sprintf("the ICORRuntimeHost::CreateDomainEx method returned an error:\r\n%s", err))
+	}
+	if hr != S_OK {
+		err = fmt.Errorf("the ICORRuntimeHost::CreateDomainEx method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	defer iu.Release()
+
+	err = iu.QueryInterface(IID_AppDomain, unsafe.Pointer(&pAppDomain))
+	return
+}
+
+// CreateDomainSetup Gets an interface pointer of type IAppDomainSetup to an AppDomainSetup instance. IAppDomainSetup
+// provides methods to configure aspects of an application domain before it is created.
+// HRESULT CreateDomainSetup (
+//
+//	[out] IUnknown** pAppDomainSetup
+//
+// );
+// https://docs.microsoft.com/en-us/dotnet/framework/unmanaged-api/hosting/icorruntimehost-createdomainsetup-method
+func (obj *ICORRuntimeHost) CreateDomainSetup() (pAppDomainSetup *IUnknown, err error) {
+	debugPrint("Entering into icorruntimehost.CreateDomainSetup()...")
+	hr, _, err := syscall.SyscallN(
+		obj.vtbl.CreateDomainSetup,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&pAppDomainSetup)),
+	)
+	if err != syscall.Errno(0) {
+		debugPrint(fmt.Sprintf("the ICORRuntimeHost::CreateDomainSetup method returned an error:\r\n%s", err))
+	}
+	if hr != S_OK {
+		err = fmt.Errorf("the ICORRuntimeHost::CreateDomainSetup method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	err = nil
+	return
+}
+
+// CreateDomainWithSetup creates an application domain configured by setup, with an AppDomainSetup instance obtained
+// from CreateDomainSetup and passed to CreateDomainEx
+func (obj *ICORRuntimeHost) CreateDomainWithSetup(FriendlyName string, setup DomainSetup) (pAppDomain *AppDomain, err error) {
+	debugPrint("Entering into icorruntimehost.CreateDomainWithSetup()...")
+	unknown, err := obj.CreateDomainSetup()
+	if err != nil {
+		return
+	}
+	appDomainSetup, err := newObject(unknown)
+	if err != nil {
+		return
+	}
+	defer appDomainSetup.Release()
+	if err = setup.apply(appDomainSetup); err != nil {
+		return nil, fmt.Errorf("there was an error configuring the %s application domain:\n%s", FriendlyName, err)
+	}
+	return obj.CreateDomainEx(FriendlyName, appDomainSetup.Unknown(), nil)
+}
+
+// apply sets the non-empty fields of the DomainSetup on an AppDomainSetup instance
+func (setup DomainSetup) apply(appDomainSetup *Object) error {
+	properties := []struct {
+		name  string
+		value any
+		set   bool
+	}{
+		{"ApplicationBase", setup.ApplicationBase, setup.ApplicationBase != ""},
+		{"ConfigurationFile", setup.ConfigurationFile, setup.ConfigurationFile != ""},
+		{"PrivateBinPath", setup.PrivateBinPath, setup.PrivateBinPath != ""},
+		// ShadowCopyFiles is a string property holding "true" or "false"
+		{"ShadowCopyFiles", "true", setup.ShadowCopyFiles},
+		// The binder converts the underlying Int32 to the enumeration
+		{"LoaderOptimization", int32(setup.LoaderOptimization), setup.LoaderOptimization != LoaderOptimization_NotSpecified},
+		{"DisallowBindingRedirects", true, setup.DisallowBindingRedirects},
+	}
+	for _, property := range properties {
+		if !property.set {
+			continue
+		}
+		if err := appDomainSetup.SetProperty(property.name, property.value); err != nil {
+			return err
+		}
+	}
+	if setup.ConfigurationBytes != nil {
+		if _, err := appDomainSetup.Call("SetConfigurationBytes", setup.ConfigurationBytes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// friendlyName converts the optional friendly name of a domain to a null terminated LPCWSTR, null when it is empty
+func friendlyName(name string) (*uint16, error) {
+	if name == "" {
+		return nil, nil
+	}
+	pwzFriendlyName, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, fmt.Errorf("the %q friendly name is not valid:\n%s", name, err)
+	}
+	return pwzFriendlyName, nil
+}
+
 func (obj *ICORRuntimeHost) GetDomain(dName string) (pAppDomain *AppDomain, err error) {
 	hEnum, err := obj.EnumDomains()
 	if err != nil {
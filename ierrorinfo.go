@@ -45,9 +45,10 @@ type IErrorInfoVtbl struct {
 //	BSTR *pbstrDescription);
 //
 // https://docs.microsoft.com/en-us/previous-versions/windows/desktop/ms714318(v=vs.85)
-func (obj *IErrorInfo) GetDescription() (pbstrDescription *string, err error) {
+func (obj *IErrorInfo) GetDescription() (description string, err error) {
 	debugPrint("Entering into ierrorinfo.GetDescription()...")
 
+	var pbstrDescription BSTR
 	hr, _, err := syscall.SyscallN(
 		obj.vtbl.GetDescription,
 		uintptr(unsafe.Pointer(obj)),
@@ -63,6 +64,8 @@ func (obj *IErrorInfo) GetDescription() (pbstrDescription *string, err error) {
 		return
 	}
 	err = nil
+	description = pbstrDescription.String()
+	pbstrDescription.Free()
 	return
 }
 
@@ -0,0 +1,203 @@
+package clr
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+const (
+	// COMIMAGE_FLAGS_ILONLY the image contains only IL code
+	COMIMAGE_FLAGS_ILONLY uint32 = 0x00000001
+	// COMIMAGE_FLAGS_32BITREQUIRED the image can only be loaded into a 32-bit process
+	COMIMAGE_FLAGS_32BITREQUIRED uint32 = 0x00000002
+	// COMIMAGE_FLAGS_STRONGNAMESIGNED the image has a strong name signature
+	COMIMAGE_FLAGS_STRONGNAMESIGNED uint32 = 0x00000008
+	// COMIMAGE_FLAGS_NATIVE_ENTRYPOINT the entry point is an RVA to native code instead of a metadata token
+	COMIMAGE_FLAGS_NATIVE_ENTRYPOINT uint32 = 0x00000010
+	// COMIMAGE_FLAGS_32BITPREFERRED the image prefers to be loaded into a 32-bit process
+	COMIMAGE_FLAGS_32BITPREFERRED uint32 = 0x00020000
+)
+
+// ImageInfo describes a managed PE image before it is handed to the CLR
+type ImageInfo struct {
+	// Machine is the IMAGE_FILE_MACHINE_* value of the PE file header
+	Machine uint16
+	// IsDLL is true for a library and false for an executable
+	IsDLL bool
+	// Managed is true when the image has a CLR (COM descriptor) header
+	Managed bool
+	// RuntimeVersion is the CLR version string from the metadata root, e.g. "v4.0.30319"
+	RuntimeVersion string
+	// Flags are the COMIMAGE_FLAGS_* from the CLR header
+	Flags uint32
+	// EntryPointToken is the MethodDef token of the managed entry point, 0 when there is none
+	EntryPointToken uint32
+	// MVID is the module version identifier from the metadata #GUID heap
+	MVID GUID
+}
+
+// TargetRuntime returns the major version prefix of RuntimeVersion, e.g. "v4", suitable for LoadCLR
+func (info *ImageInfo) TargetRuntime() string {
+	if i := strings.Index(info.RuntimeVersion, "."); i > 0 {
+		return info.RuntimeVersion[:i]
+	}
+	return info.RuntimeVersion
+}
+
+// imageCor20Header is the CLR header of a managed image
+// https://docs.microsoft.com/en-us/dotnet/standard/assembly/file-format
+type imageCor20Header struct {
+	Cb                  uint32
+	MajorRuntimeVersion uint16
+	MinorRuntimeVersion uint16
+	MetaData            pe.DataDirectory
+	Flags               uint32
+	EntryPointToken     uint32
+}
+
+// InspectImage parses the PE headers and the metadata root of an assembly without loading it into the CLR.
+// Images without a CLR header are reported with Managed set to false
+func InspectImage(rawBytes []byte) (*ImageInfo, error) {
+	f, err := pe.NewFile(bytes.NewReader(rawBytes))
+	if err != nil {
+		return nil, fmt.Errorf("there was an error parsing the PE image:\n%s", err)
+	}
+	defer f.Close()
+
+	info := &ImageInfo{
+		Machine: f.FileHeader.Machine,
+		IsDLL:   f.FileHeader.Characteristics&pe.IMAGE_FILE_DLL != 0,
+	}
+
+	var dirs []pe.DataDirectory
+	switch oh := f.OptionalHeader.(type) {
+	// debug/pe keeps at most 16 of the data directories declared by the optional header
+	case *pe.OptionalHeader32:
+		dirs = oh.DataDirectory[:min(oh.NumberOfRvaAndSizes, uint32(len(oh.DataDirectory)))]
+	case *pe.OptionalHeader64:
+		dirs = oh.DataDirectory[:min(oh.NumberOfRvaAndSizes, uint32(len(oh.DataDirectory)))]
+	}
+	if len(dirs) <= pe.IMAGE_DIRECTORY_ENTRY_COM_DESCRIPTOR || dirs[pe.IMAGE_DIRECTORY_ENTRY_COM_DESCRIPTOR].VirtualAddress == 0 {
+		return info, nil
+	}
+	info.Managed = true
+
+	var header imageCor20Header
+	raw, err := readRVA(f, len(rawBytes), dirs[pe.IMAGE_DIRECTORY_ENTRY_COM_DESCRIPTOR].VirtualAddress, uint32(binary.Size(header)))
+	if err != nil {
+		return nil, fmt.Errorf("there was an error reading the CLR header:\n%s", err)
+	}
+	if err = binary.Read(bytes.NewReader(raw), binary.LittleEndian, &header); err != nil {
+		return nil, err
+	}
+	info.Flags = header.Flags
+	if header.Flags&COMIMAGE_FLAGS_NATIVE_ENTRYPOINT == 0 {
+		info.EntryPointToken = header.EntryPointToken
+	}
+
+	metadata, err := readRVA(f, len(rawBytes), header.MetaData.VirtualAddress, header.MetaData.Size)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error reading the metadata:\n%s", err)
+	}
+	if err = info.parseMetadata(metadata); err != nil {
+		return nil, fmt.Errorf("there was an error parsing the metadata:\n%s", err)
+	}
+	return info, nil
+}
+
+// readRVA reads size bytes at a relative virtual address of an image of imageSize bytes. The bounds are computed in
+// 64 bits so a crafted size can't wrap around them, and the raw data of the section must be inside the image
+func readRVA(f *pe.File, imageSize int, rva, size uint32) ([]byte, error) {
+	for _, s := range f.Sections {
+		if rva < s.VirtualAddress || uint64(rva)+uint64(size) > uint64(s.VirtualAddress)+uint64(s.Size) {
+			continue
+		}
+		if uint64(s.Offset)+uint64(rva-s.VirtualAddress)+uint64(size) > uint64(imageSize) {
+			return nil, fmt.Errorf("RVA 0x%x with size %d is past the end of the image", rva, size)
+		}
+		b := make([]byte, size)
+		if _, err := s.ReadAt(b, int64(rva-s.VirtualAddress)); err != nil {
+			return nil, err
+		}
+		return b, nil
+	}
+	return nil, fmt.Errorf("RVA 0x%x with size %d is not inside any section", rva, size)
+}
+
+// parseMetadata reads the version string from the metadata root and the MVID from the Module table
+// ECMA-335 II.24.2.1 Metadata root
+func (info *ImageInfo) parseMetadata(md []byte) error {
+	le := binary.LittleEndian
+	if len(md) < 16 || le.Uint32(md) != 0x424a5342 {
+		return fmt.Errorf("the metadata root does not start with the BSJB signature")
+	}
+	length := uint64(le.Uint32(md[12:]))
+	if 16+length+4 > uint64(len(md)) {
+		return fmt.Errorf("the metadata version string length %d is out of range", length)
+	}
+	info.RuntimeVersion = strings.TrimRight(string(md[16:16+length]), "\x00")
+
+	// Stream headers follow the version string, flags and stream count
+	offset := uint32(16 + length + 2)
+	streams := le.Uint16(md[offset:])
+	offset += 2
+	heaps := make(map[string][]byte, streams)
+	for i := uint16(0); i < streams; i++ {
+		if uint64(offset+8) > uint64(len(md)) {
+			return fmt.Errorf("stream header %d is out of range", i)
+		}
+		start, size := le.Uint32(md[offset:]), le.Uint32(md[offset+4:])
+		offset += 8
+		end := bytes.IndexByte(md[offset:], 0)
+		if end < 0 {
+			return fmt.Errorf("stream header %d has an unterminated name", i)
+		}
+		name := string(md[offset : offset+uint32(end)])
+		// Names are null terminated and padded to a multiple of four bytes
+		offset += (uint32(end) + 4) &^ 3
+		if uint64(start)+uint64(size) > uint64(len(md)) {
+			return fmt.Errorf("the %s stream is out of range", name)
+		}
+		heaps[name] = md[start : start+size]
+	}
+
+	tables, ok := heaps["#~"]
+	if !ok || len(tables) < 24 {
+		return nil
+	}
+	// ECMA-335 II.24.2.6 #~ stream, the Module table is table 0 and its only row follows the row counts
+	heapSizes := tables[6]
+	valid := le.Uint64(tables[8:])
+	if valid&1 == 0 {
+		return nil
+	}
+	row := uint32(24)
+	for bit := 0; bit < 64; bit++ {
+		if valid&(1<<bit) != 0 {
+			row += 4
+		}
+	}
+	stringIndex, guidIndex := uint32(2), uint32(2)
+	if heapSizes&0x01 != 0 {
+		stringIndex = 4
+	}
+	if heapSizes&0x02 != 0 {
+		guidIndex = 4
+	}
+	// Generation, Name and then Mvid
+	mvid := row + 2 + stringIndex
+	if mvid+guidIndex > uint32(len(tables)) {
+		return fmt.Errorf("the Module table is out of range")
+	}
+	var index uint32
+	if guidIndex == 4 {
+		index = le.Uint32(tables[mvid:])
+	} else {
+		index = uint32(le.Uint16(tables[mvid:]))
+	}
+	info.MVID, _ = GUIDFromHeap(heaps["#GUID"], index)
+	return nil
+}
@@ -0,0 +1,169 @@
+package clr
+
+import (
+	"bytes"
+	"debug/pe"
+	"encoding/binary"
+	"io"
+	"testing"
+)
+
+// craftImage returns a PE image without sections whose optional header declares directories data directories
+func craftImage(t *testing.T, pe64 bool, directories uint32) []byte {
+	t.Helper()
+	var optional bytes.Buffer
+	var machine uint16
+	if pe64 {
+		machine = pe.IMAGE_FILE_MACHINE_AMD64
+		binary.Write(&optional, binary.LittleEndian, pe.OptionalHeader64{Magic: 0x20b, NumberOfRvaAndSizes: directories})
+	} else {
+		machine = pe.IMAGE_FILE_MACHINE_I386
+		binary.Write(&optional, binary.LittleEndian, pe.OptionalHeader32{Magic: 0x10b, NumberOfRvaAndSizes: directories})
+	}
+	// The structures hold 16 directories, the extra ones follow them
+	optional.Truncate(optional.Len() - 16*binary.Size(pe.DataDirectory{}))
+	for i := uint32(0); i < directories; i++ {
+		binary.Write(&optional, binary.LittleEndian, pe.DataDirectory{})
+	}
+
+	var image bytes.Buffer
+	dos := make([]byte, 0x40)
+	copy(dos, "MZ")
+	binary.LittleEndian.PutUint32(dos[0x3c:], 0x40)
+	image.Write(dos)
+	image.WriteString("PE\x00\x00")
+	binary.Write(&image, binary.LittleEndian, pe.FileHeader{
+		Machine:              machine,
+		SizeOfOptionalHeader: uint16(optional.Len()),
+		Characteristics:      pe.IMAGE_FILE_EXECUTABLE_IMAGE | pe.IMAGE_FILE_DLL,
+	})
+	image.Write(optional.Bytes())
+	return image.Bytes()
+}
+
+func TestInspectImageDataDirectories(t *testing.T) {
+	tests := []struct {
+		name        string
+		pe64        bool
+		directories uint32
+	}{
+		{"PE32 without directories", false, 0},
+		{"PE32 with 16 directories", false, 16},
+		{"PE32 with 17 directories", false, 17},
+		{"PE32+ with 2 directories", true, 2},
+		{"PE32+ with 16 directories", true, 16},
+		{"PE32+ with 17 directories", true, 17},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := InspectImage(craftImage(t, tt.pe64, tt.directories))
+			if err != nil {
+				t.Fatalf("InspectImage returned an error: %s", err)
+			}
+			if info.Managed {
+				t.Errorf("the image has no CLR header but Managed is true")
+			}
+			if !info.IsDLL {
+				t.Errorf("the image is a DLL but IsDLL is false")
+			}
+		})
+	}
+}
+
+func TestInspectImageNotPE(t *testing.T) {
+	if _, err := InspectImage([]byte("not a PE image")); err == nil {
+		t.Errorf("InspectImage accepted an invalid image")
+	}
+}
+
+// craftMetadata returns a metadata root declaring a version string of length bytes followed by the streams
+func craftMetadata(length uint32, version string, streams ...[]byte) []byte {
+	var md bytes.Buffer
+	binary.Write(&md, binary.LittleEndian, []uint32{0x424a5342, 0x00010001, 0, length})
+	md.WriteString(version)
+	binary.Write(&md, binary.LittleEndian, []uint16{0, uint16(len(streams))})
+	for _, stream := range streams {
+		md.Write(stream)
+	}
+	return md.Bytes()
+}
+
+// streamHeader returns the header of a stream of size bytes at offset start
+func streamHeader(start, size uint32, name string) []byte {
+	header := binary.LittleEndian.AppendUint32(nil, start)
+	header = binary.LittleEndian.AppendUint32(header, size)
+	header = append(header, name...)
+	return append(header, make([]byte, 4-len(name)%4)...)
+}
+
+func TestParseMetadata(t *testing.T) {
+	tests := []struct {
+		name    string
+		md      []byte
+		version string
+		wantErr bool
+	}{
+		{"version", craftMetadata(12, "v4.0.30319\x00\x00"), "v4.0.30319", false},
+		{"empty version", craftMetadata(0, ""), "", false},
+		{"stream inside the metadata", craftMetadata(4, "v2\x00\x00", streamHeader(0, 16, "#~")), "v2", false},
+		{"no signature", []byte("BSJA000000000000"), "", true},
+		{"truncated root", []byte("BSJB"), "", true},
+		{"version past the end", craftMetadata(13, "v4.0.30319\x00\x00"), "", true},
+		// 16+length+4 wraps around 32 bits
+		{"oversized version length", craftMetadata(0xfffffff0, "v4.0.30319\x00\x00"), "", true},
+		// The version is read before the streams
+		{"oversized stream", craftMetadata(4, "v2\x00\x00", streamHeader(8, 0xfffffffc, "#~")), "v2", true},
+		{"missing stream header", craftMetadata(4, "v2\x00\x00", nil), "v2", true},
+		{"unterminated stream name", craftMetadata(4, "v2\x00\x00", []byte{0, 0, 0, 0, 0, 0, 0, 0, '#', '~'}), "v2", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info ImageInfo
+			err := info.parseMetadata(tt.md)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseMetadata returned the error %v, want an error: %t", err, tt.wantErr)
+			}
+			if info.RuntimeVersion != tt.version {
+				t.Errorf("the runtime version is %q, want %q", info.RuntimeVersion, tt.version)
+			}
+		})
+	}
+}
+
+func TestReadRVA(t *testing.T) {
+	image := make([]byte, 0x600)
+	for i := range image {
+		image[i] = byte(i)
+	}
+	section := &pe.Section{
+		SectionHeader: pe.SectionHeader{VirtualAddress: 0x1000, Size: 0x200, Offset: 0x400},
+		ReaderAt:      io.NewSectionReader(bytes.NewReader(image), 0x400, 0x200),
+	}
+	f := &pe.File{Sections: []*pe.Section{section}}
+	tests := []struct {
+		name      string
+		imageSize int
+		rva, size uint32
+		wantErr   bool
+	}{
+		{"start of the section", len(image), 0x1000, 8, false},
+		{"end of the section", len(image), 0x11f8, 8, false},
+		{"before the section", len(image), 0xff8, 8, true},
+		{"past the section", len(image), 0x11f8, 9, true},
+		// rva+size wraps around 32 bits to an address inside the section
+		{"wrapping size", len(image), 0x1100, 0xffffff00, true},
+		{"whole address space", len(image), 0x1000, 0xffffffff, true},
+		{"raw data past the end of the image", 0x500, 0x1100, 8, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := readRVA(f, tt.imageSize, tt.rva, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readRVA returned the error %v, want an error: %t", err, tt.wantErr)
+			}
+			if offset := 0x400 + int(tt.rva) - 0x1000; err == nil && !bytes.Equal(b, image[offset:offset+int(tt.size)]) {
+				t.Errorf("readRVA returned %x, want %x", b, image[offset:offset+int(tt.size)])
+			}
+		})
+	}
+}
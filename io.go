@@ -1,6 +1,3 @@
-//go:build windows
-// +build windows
-
 package clr
 
 import (
@@ -10,18 +7,9 @@ import (
 	"fmt"
 	"os"
 	"sync"
-	"syscall"
 	"time"
-
-	"golang.org/x/sys/windows"
 )
 
-// origSTDOUT is a Windows Handle to the program's original STDOUT
-var origSTDOUT = windows.Stdout
-
-// origSTDERR is a Windows Handle to the program's original STDERR
-var origSTDERR = windows.Stderr
-
 // rSTDOUT is an io.Reader for STDOUT
 var rSTDOUT *os.File
 
@@ -50,84 +38,6 @@ var errorschan = make(chan error)
 // mutex ensures exclusive access to read/write on STDOUT/STDERR by one routine at a time
 var mutex = &sync.Mutex{}
 
-// RedirectStdoutStderr redirects the program's STDOUT/STDERR to an *os.File that can be read from this Go program
-// The CLR executes assemblies outside of Go and therefore STDOUT/STDERR can't be captured using normal functions
-// Intended to be used with a Command & Control framework so STDOUT/STDERR can be captured and returned
-func RedirectStdoutStderr() (err error) {
-	// Create a new reader and writer for STDOUT
-	rSTDOUT, wSTDOUT, err = os.Pipe()
-	if err != nil {
-		err = fmt.Errorf("there was an error calling the os.Pipe() function to create a new STDOUT:\n%s", err)
-		return
-	}
-
-	// Create a new reader and writer for STDERR
-	rSTDERR, wSTDERR, err = os.Pipe()
-	if err != nil {
-		err = fmt.Errorf("there was an error calling the os.Pipe() function to create a new STDERR:\n%s", err)
-		return
-	}
-
-	kernel32 := windows.NewLazySystemDLL("kernel32.dll")
-	getConsoleWindow := kernel32.NewProc("GetConsoleWindow")
-
-	// Ensure the process has a console because if it doesn't there will be no output to capture
-	_, _, err = getConsoleWindow.Call()
-	if err != syscall.Errno(0) {
-		// https://learn.microsoft.com/en-us/windows/console/allocconsole
-		allocConsole := kernel32.NewProc("AllocConsole")
-		// BOOL WINAPI AllocConsole(void);
-		ret, _, err := allocConsole.Call()
-		// A process can be associated with only one console, so the AllocConsole function fails if the calling process
-		// already has a console. So long as any console exists we are good to go and therefore don't care about errors
-		if ret == 0 {
-			return fmt.Errorf("there was an error calling kernel32!AllocConsole with return code %d: %s", ret, err)
-		}
-
-		// Get a handle to the newly created/allocated console
-		hConsole, _, _ := getConsoleWindow.Call()
-
-		user32 := windows.NewLazySystemDLL("user32.dll")
-		showWindow := user32.NewProc("ShowWindow")
-		// Hide the console window
-		ret, _, err = showWindow.Call(hConsole, windows.SW_HIDE)
-		if err != syscall.Errno(0) {
-			return fmt.Errorf("there was an error calling user32!ShowWindow with return %+v: %s", ret, err)
-		}
-	}
-
-	// Set STDOUT/STDERR to the new files from os.Pipe()
-	// https://docs.microsoft.com/en-us/windows/console/setstdhandle
-	if err = windows.SetStdHandle(windows.STD_OUTPUT_HANDLE, windows.Handle(wSTDOUT.Fd())); err != nil {
-		err = fmt.Errorf("there was an error calling the windows.SetStdHandle function for STDOUT:\n%s", err)
-		return
-	}
-
-	if err = windows.SetStdHandle(windows.STD_ERROR_HANDLE, windows.Handle(wSTDERR.Fd())); err != nil {
-		err = fmt.Errorf("there was an error calling the windows.SetStdHandle function for STDERR:\n%s", err)
-		return
-	}
-
-	// Start STDOUT/STDERR buffer and collection
-	go BufferStdout()
-	go BufferStderr()
-
-	return
-}
-
-// RestoreStdoutStderr returns the program's original STDOUT/STDERR handles before they were redirected an *os.File
-// Previously instantiated CLRs will continue to use the REDIRECTED STDOUT/STDERR handles and will not resume
-// using the restored handles
-func RestoreStdoutStderr() error {
-	if err := windows.SetStdHandle(windows.STD_OUTPUT_HANDLE, origSTDOUT); err != nil {
-		return fmt.Errorf("there was an error calling the windows.SetStdHandle function to restore the original STDOUT handle:\n%s", err)
-	}
-	if err := windows.SetStdHandle(windows.STD_ERROR_HANDLE, origSTDERR); err != nil {
-		return fmt.Errorf("there was an error calling the windows.SetStdHandle function to restore the original STDERR handle:\n%s", err)
-	}
-	return nil
-}
-
 // ReadStdoutStderr reads from the REDIRECTED STDOUT/STDERR
 // Only use when RedirectStdoutStderr was previously called
 func ReadStdoutStderr() (stdout string, stderr string, err error) {
@@ -72,8 +72,8 @@ func (obj *ISupportErrorInfo) AddRef() (count uint32, err error) {
 		return 0, fmt.Errorf("the IUnknown::AddRef method returned an error:\r\n%s", err)
 	}
 	err = nil
-	// Unable to avoid misuse of unsafe.Pointer because the Windows API call returns the safeArray pointer in the "ret" value. This is a go vet false positive
-	count = *(*uint32)(unsafe.Pointer(ret))
+	// The new reference count is returned directly in the "ret" value
+	count = uint32(ret)
 	return
 }
 
@@ -90,8 +90,8 @@ func (obj *ISupportErrorInfo) Release() (count uint32, err error) {
 		return 0, fmt.Errorf("the IUnknown::Release method returned an error:\r\n%s", err)
 	}
 	err = nil
-	// Unable to avoid misuse of unsafe.Pointer because the Windows API call returns the safeArray pointer in the "ret" value. This is a go vet false positive
-	count = *(*uint32)(unsafe.Pointer(ret))
+	// The new reference count is returned directly in the "ret" value
+	count = uint32(ret)
 	return
 }
 
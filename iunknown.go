@@ -11,25 +11,6 @@ import (
 	"golang.org/x/sys/windows"
 )
 
-type IUnknown struct {
-	vtbl *IUnknownVtbl
-}
-
-// IUnknownVtbl Enables clients to get pointers to other interfaces on a given object through the
-// QueryInterface method, and manage the existence of the object through the AddRef and Release methods.
-// All other COM interfaces are inherited, directly or indirectly, from IUnknown. Therefore, the three
-// methods in IUnknown are the first entries in the vtable for every interface.
-// https://docs.microsoft.com/en-us/windows/win32/api/unknwn/nn-unknwn-iunknown
-type IUnknownVtbl struct {
-	// QueryInterface Retrieves pointers to the supported interfaces on an object.
-	QueryInterface uintptr
-	// AddRef Increments the reference count for an interface pointer to a COM object.
-	// You should call this method whenever you make a copy of an interface pointer.
-	AddRef uintptr
-	// Release Decrements the reference count for an interface on a COM object.
-	Release uintptr
-}
-
 // QueryInterface queries a COM object for a pointer to one of its interface;
 // identifying the interface by a reference to its interface identifier (IID).
 // If the COM object implements the interface, then it returns a pointer to that interface after calling IUnknown::AddRef on it.
@@ -71,8 +52,8 @@ func (obj *IUnknown) AddRef() (count uint32, err error) {
 		return 0, fmt.Errorf("the IUnknown::AddRef method returned an error:\r\n%s", err)
 	}
 	err = nil
-	// Unable to avoid misuse of unsafe.Pointer because the Windows API call returns the safeArray pointer in the "ret" value. This is a go vet false positive
-	count = *(*uint32)(unsafe.Pointer(ret))
+	// The new reference count is returned directly in the "ret" value
+	count = uint32(ret)
 	return
 }
 
@@ -89,7 +70,17 @@ func (obj *IUnknown) Release() (count uint32, err error) {
 		return 0, fmt.Errorf("the IUnknown::Release method returned an error:\r\n%s", err)
 	}
 	err = nil
-	// Unable to avoid misuse of unsafe.Pointer because the Windows API call returns the safeArray pointer in the "ret" value. This is a go vet false positive
-	count = *(*uint32)(unsafe.Pointer(ret))
+	// The new reference count is returned directly in the "ret" value
+	count = uint32(ret)
 	return
 }
+
+// comAddRef calls IUnknown::AddRef on an interface pointer of any type
+func comAddRef(p unsafe.Pointer) {
+	(*IUnknown)(p).AddRef()
+}
+
+// comRelease calls IUnknown::Release on an interface pointer of any type
+func comRelease(p unsafe.Pointer) {
+	(*IUnknown)(p).Release()
+}
@@ -0,0 +1,142 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// from mscorlib.tlh
+
+// MemberInfo is the _MemberInfo COM interface, the members shared by the _PropertyInfo, _FieldInfo, _ConstructorInfo
+// and _MethodInfo interfaces returned by Type. Those interfaces start with the same virtual table
+type MemberInfo struct {
+	vtbl *MemberInfoVtbl
+}
+
+// MemberInfoVtbl Obtains information about the attributes of a member and provides access to member metadata.
+// Inheritance: Object -> MemberInfo
+// MemberInfo Class: https://docs.microsoft.com/en-us/dotnet/api/system.reflection.memberinfo?view=net-5.0
+type MemberInfoVtbl struct {
+	QueryInterface        uintptr
+	AddRef                uintptr
+	Release               uintptr
+	GetTypeInfoCount      uintptr
+	GetTypeInfo           uintptr
+	GetIDsOfNames         uintptr
+	Invoke                uintptr
+	get_ToString          uintptr
+	Equals                uintptr
+	GetHashCode           uintptr
+	GetType               uintptr
+	get_MemberType        uintptr
+	get_name              uintptr
+	get_DeclaringType     uintptr
+	get_ReflectedType     uintptr
+	GetCustomAttributes   uintptr
+	GetCustomAttributes_2 uintptr
+	IsDefined             uintptr
+}
+
+func (obj *MemberInfo) AddRef() uintptr {
+	ret, _, _ := syscall.SyscallN(
+		obj.vtbl.AddRef,
+		uintptr(unsafe.Pointer(obj)),
+	)
+	return ret
+}
+
+func (obj *MemberInfo) Release() uintptr {
+	ret, _, _ := syscall.SyscallN(
+		obj.vtbl.Release,
+		uintptr(unsafe.Pointer(obj)),
+	)
+	return ret
+}
+
+// GetString returns a string that represents the member, such as "Int32 Count" for a property
+//
+//	virtual HRESULT __stdcall get_ToString (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.object.tostring?view=net-5.0
+func (obj *MemberInfo) GetString() (str string, err error) {
+	debugPrint("Entering into memberinfo.GetString()...")
+	var bstr BSTR
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_ToString,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&bstr)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MemberInfo::ToString method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	str = bstr.String()
+	bstr.Free()
+	return
+}
+
+// GetName returns the name of the member
+//
+//	virtual HRESULT __stdcall get_name (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.memberinfo.name?view=net-5.0
+func (obj *MemberInfo) GetName() (name string, err error) {
+	debugPrint("Entering into memberinfo.GetName()...")
+	var bstr BSTR
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_name,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&bstr)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MemberInfo::get_name method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	name = bstr.String()
+	bstr.Free()
+	return
+}
+
+// GetMemberType returns the kind of the member, such as MemberTypes_Property
+//
+//	virtual HRESULT __stdcall get_MemberType (
+//	/*[out,retval]*/ enum MemberTypes * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.memberinfo.membertype?view=net-5.0
+func (obj *MemberInfo) GetMemberType() (memberType MemberTypes, err error) {
+	debugPrint("Entering into memberinfo.GetMemberType()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_MemberType,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&memberType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MemberInfo::get_MemberType method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
+
+// GetDeclaringType returns the type that declares the member
+//
+//	virtual HRESULT __stdcall get_DeclaringType (
+//	/*[out,retval]*/ struct _Type * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.memberinfo.declaringtype?view=net-5.0
+func (obj *MemberInfo) GetDeclaringType() (declaringType *Type, err error) {
+	debugPrint("Entering into memberinfo.GetDeclaringType()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_DeclaringType,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&declaringType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MemberInfo::get_DeclaringType method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
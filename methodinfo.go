@@ -5,6 +5,7 @@ package clr
 
 import (
 	"fmt"
+	"runtime"
 	"syscall"
 	"unsafe"
 
@@ -100,6 +101,42 @@ func (obj *MethodInfo) Release() uintptr {
 	return ret
 }
 
+// GetParameters Gets the parameters of the specified method or constructor.
+//
+//	virtual HRESULT __stdcall GetParameters (
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.methodbase.getparameters?view=net-5.0
+func (obj *MethodInfo) GetParameters() (parameters *SafeArray, err error) {
+	debugPrint("Entering into methodinfo.GetParameters()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetParameters,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&parameters)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MethodInfo::GetParameters method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	return
+}
+
+// GetParameterCount returns the number of parameters the method takes
+func (obj *MethodInfo) GetParameterCount() (int, error) {
+	debugPrint("Entering into methodinfo.GetParameterCount()...")
+	parameters, err := obj.GetParameters()
+	if err != nil {
+		return 0, err
+	}
+	array, err := WrapSafeArray[*IUnknown](parameters)
+	if err != nil {
+		SafeArrayDestroy(parameters)
+		return 0, err
+	}
+	defer array.Destroy()
+	return array.Len()
+}
+
 // Invoke_3 Invokes the method or constructor reflected by this MethodInfo instance.
 //
 //	virtual HRESULT __stdcall Invoke_3 (
@@ -108,16 +145,18 @@ func (obj *MethodInfo) Release() uintptr {
 //	/*[out,retval]*/ VARIANT * pRetVal ) = 0;
 //
 // https://docs.microsoft.com/en-us/dotnet/api/system.reflection.methodbase.invoke?view=net-5.0
-func (obj *MethodInfo) Invoke_3(variantObj Variant, parameters *SafeArray) (err error) {
+//
+// The returned Variant holds the method's return value, VT_EMPTY for methods that return void, and is owned by the
+// caller who must release it with Variant.Clear. Use FromVariant to convert it into a Go value.
+func (obj *MethodInfo) Invoke_3(variantObj Variant, parameters *SafeArray) (result Variant, err error) {
 	debugPrint("Entering into methodinfo.Invoke_3()...")
-	var pRetVal *Variant
-	hr, _, err := syscall.SyscallN(
-		obj.vtbl.Invoke_3,
-		uintptr(unsafe.Pointer(obj)),
-		uintptr(unsafe.Pointer(&variantObj)),
-		uintptr(unsafe.Pointer(parameters)),
-		uintptr(unsafe.Pointer(pRetVal)),
-	)
+	// The VARIANT is passed by value following the calling convention of the architecture
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	pinner.Pin(&result)
+	args := appendVariantArg([]uintptr{uintptr(unsafe.Pointer(obj))}, &variantObj, &pinner)
+	args = append(args, uintptr(unsafe.Pointer(parameters)), uintptr(unsafe.Pointer(&result)))
+	hr, _, err := syscall.SyscallN(obj.vtbl.Invoke_3, args...)
 	if err != syscall.Errno(0) {
 		err = fmt.Errorf("the MethodInfo::Invoke_3 method returned an error:\r\n%s", err)
 		return
@@ -146,31 +185,26 @@ func (obj *MethodInfo) Invoke_3(variantObj Variant, parameters *SafeArray) (err
 		iErrorInfo, errG := GetErrorInfo()
 		if errG != nil {
 			err = fmt.Errorf("there was an error getting the IErrorInfo object:\r\n%s", errG)
-			return err
+			return
 		}
 
 		// Read the IErrorInfo description
 		desc, errD := iErrorInfo.GetDescription()
 		if errD != nil {
 			err = fmt.Errorf("the IErrorInfo::GetDescription method returned an error:\r\n%s", errD)
-			return err
+			return
 		}
-		if desc == nil {
+		if desc == "" {
 			err = fmt.Errorf("the Assembly::Invoke_3 method returned a non-zero HRESULT: 0x%x", hr)
 			return
 		}
-		err = fmt.Errorf("the Assembly::Invoke_3 method returned a non-zero HRESULT: 0x%x with an IErrorInfo description of: %s", hr, *desc)
+		err = fmt.Errorf("the Assembly::Invoke_3 method returned a non-zero HRESULT: 0x%x with an IErrorInfo description of: %s", hr, desc)
 		return
 	}
 	if hr != S_OK {
 		err = fmt.Errorf("the Assembly::Invoke_3 method returned a non-zero HRESULT: 0x%x", hr)
 		return
 	}
-
-	if pRetVal != nil {
-		err = fmt.Errorf("the Assembly::Invoke_3 method returned a non-zero pRetVal: %+v", pRetVal)
-		return
-	}
 	err = nil
 	return
 }
@@ -181,7 +215,7 @@ func (obj *MethodInfo) Invoke_3(variantObj Variant, parameters *SafeArray) (err
 // https://docs.microsoft.com/en-us/dotnet/api/system.object.tostring?view=net-5.0#System_Object_ToString
 func (obj *MethodInfo) GetString() (str string, err error) {
 	debugPrint("Entering into methodinfo.GetString()...")
-	var object *string
+	var object BSTR
 	hr, _, err := syscall.SyscallN(
 		obj.vtbl.get_ToString,
 		uintptr(unsafe.Pointer(obj)),
@@ -196,6 +230,250 @@ func (obj *MethodInfo) GetString() (str string, err error) {
 		return
 	}
 	err = nil
-	str = ReadUnicodeStr(unsafe.Pointer(object))
+	str = object.String()
+	object.Free()
+	return
+}
+
+// GetName returns the name of the method
+//
+//	virtual HRESULT __stdcall get_name (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.memberinfo.name?view=net-5.0
+func (obj *MethodInfo) GetName() (name string, err error) {
+	debugPrint("Entering into methodinfo.GetName()...")
+	var bstr BSTR
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_name,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&bstr)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MethodInfo::get_name method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	name = bstr.String()
+	bstr.Free()
+	return
+}
+
+// GetDeclaringType returns the type that declares the method, nil for global methods
+//
+//	virtual HRESULT __stdcall get_DeclaringType (
+//	/*[out,retval]*/ struct _Type * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.memberinfo.declaringtype?view=net-5.0
+func (obj *MethodInfo) GetDeclaringType() (declaringType *Type, err error) {
+	debugPrint("Entering into methodinfo.GetDeclaringType()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_DeclaringType,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&declaringType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MethodInfo::get_DeclaringType method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
+
+// GetReturnType returns the return type of the method, System.Void for methods that return nothing
+//
+//	virtual HRESULT __stdcall get_returnType (
+//	/*[out,retval]*/ struct _Type * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.methodinfo.returntype?view=net-5.0
+func (obj *MethodInfo) GetReturnType() (returnType *Type, err error) {
+	debugPrint("Entering into methodinfo.GetReturnType()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_returnType,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&returnType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MethodInfo::get_returnType method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
+
+// IsStatic returns true if the method is static
+//
+//	virtual HRESULT __stdcall get_IsStatic (
+//	/*[out,retval]*/ VARIANT_BOOL * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.methodbase.isstatic?view=net-5.0
+func (obj *MethodInfo) IsStatic() (bool, error) {
+	debugPrint("Entering into methodinfo.IsStatic()...")
+	var b int16
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_IsStatic,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&b)),
+	)
+	if hr != S_OK {
+		return false, fmt.Errorf("the MethodInfo::get_IsStatic method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return b != 0, nil
+}
+
+// GetAttributes returns the attributes of the method, such as its accessibility
+//
+//	virtual HRESULT __stdcall get_Attributes (
+//	/*[out,retval]*/ enum MethodAttributes * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.methodbase.attributes?view=net-5.0
+func (obj *MethodInfo) GetAttributes() (attributes MethodAttributes, err error) {
+	debugPrint("Entering into methodinfo.GetAttributes()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_Attributes,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&attributes)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the MethodInfo::get_Attributes method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
+
+// Signature reads the name, declaring type, return type, attributes and parameters of the method
+func (obj *MethodInfo) Signature() (signature *Signature, err error) {
+	debugPrint("Entering into methodinfo.Signature()...")
+	signature = &Signature{}
+	if signature.Name, err = obj.GetName(); err != nil {
+		return nil, err
+	}
+	if signature.IsStatic, err = obj.IsStatic(); err != nil {
+		return nil, err
+	}
+	if signature.Attributes, err = obj.GetAttributes(); err != nil {
+		return nil, err
+	}
+
+	declaringType, err := obj.GetDeclaringType()
+	if err != nil {
+		return nil, err
+	}
+	if declaringType != nil {
+		signature.DeclaringType, err = declaringType.GetFullName()
+		declaringType.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	returnType, err := obj.GetReturnType()
+	if err != nil {
+		return nil, err
+	}
+	signature.ReturnType, err = returnType.GetString()
+	returnType.Release()
+	if err != nil {
+		return nil, err
+	}
+
+	psa, err := obj.GetParameters()
+	if err != nil {
+		return nil, err
+	}
+	parameters, err := interfacesFromSafeArray[IUnknown](psa)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, parameter := range parameters {
+			parameter.Release()
+		}
+	}()
+	for _, parameter := range parameters {
+		p, err := readParameter(parameter)
+		if err != nil {
+			return nil, err
+		}
+		signature.Parameters = append(signature.Parameters, p)
+	}
+	return signature, nil
+}
+
+// readParameter reads a ParameterInfo with late binding, its _ParameterInfo interface has no properties
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.parameterinfo?view=net-5.0
+func readParameter(parameter *IUnknown) (p Parameter, err error) {
+	parameterType, err := parameter.GetType()
+	if err != nil {
+		return
+	}
+	defer parameterType.Release()
+	get := func(name string) (any, error) {
+		return parameterType.InvokeMember(name, BindingFlags_GetProperty|BindingFlags_Public|BindingFlags_Instance, parameter)
+	}
+
+	name, err := get("Name")
+	if err != nil {
+		return
+	}
+	p.Name, _ = name.(string)
+	position, err := get("Position")
+	if err != nil {
+		return
+	}
+	if position, ok := position.(int32); ok {
+		p.Position = int(position)
+	}
+	isOut, err := get("IsOut")
+	if err != nil {
+		return
+	}
+	p.IsOut, _ = isOut.(bool)
+	isOptional, err := get("IsOptional")
+	if err != nil {
+		return
+	}
+	p.IsOptional, _ = isOptional.(bool)
+
+	// HasDefaultValue was added in .NET Framework 4.5, before that only optional parameters have a default value
+	if hasDefault, errD := get("HasDefaultValue"); errD == nil {
+		p.HasDefault, _ = hasDefault.(bool)
+	} else {
+		p.HasDefault = p.IsOptional
+	}
+	if p.HasDefault {
+		if p.Default, err = get("DefaultValue"); err != nil {
+			return
+		}
+		// Optional parameters without a default value have the Missing value
+		if _, missing := p.Default.(SCode); missing {
+			p.HasDefault, p.Default = false, nil
+		}
+	}
+
+	managedType, err := get("ParameterType")
+	if err != nil {
+		return
+	}
+	if unknown, ok := managedType.(*IUnknown); ok && unknown != nil {
+		var typeOfParameter *Type
+		err = unknown.QueryInterface(IID__Type, unsafe.Pointer(&typeOfParameter))
+		unknown.Release()
+		if err != nil {
+			return
+		}
+		p.Type, err = typeOfParameter.GetString()
+		typeOfParameter.Release()
+		if err != nil {
+			return
+		}
+	}
+
+	attributes, err := parameterType.InvokeMember("GetCustomAttributes", BindingFlags_InvokeMethod|BindingFlags_Public|BindingFlags_Instance, parameter, false)
+	if err != nil {
+		return
+	}
+	for _, attribute := range interfacesOf(attributes) {
+		if attributeType, errT := attribute.GetType(); errT == nil {
+			attributeName, _ := attributeType.GetFullName()
+			attributeType.Release()
+			p.IsParamArray = p.IsParamArray || attributeName == "System.ParamArrayAttribute"
+		}
+		attribute.Release()
+	}
 	return
 }
@@ -0,0 +1,75 @@
+package clr
+
+import "unsafe"
+
+const (
+	// FADF_AUTO the array is allocated on the stack
+	FADF_AUTO uint16 = 0x0001
+	// FADF_STATIC the array is statically allocated
+	FADF_STATIC uint16 = 0x0002
+	// FADF_EMBEDDED the array is embedded in a structure
+	FADF_EMBEDDED uint16 = 0x0004
+	// FADF_FIXEDSIZE the array may not be resized or reallocated
+	FADF_FIXEDSIZE uint16 = 0x0010
+	// FADF_RECORD the array contains records, the IRecordInfo is stored before the descriptor
+	FADF_RECORD uint16 = 0x0020
+	// FADF_HAVEIID the array has an IID identifying the interface, stored in the 16 bytes before the descriptor
+	FADF_HAVEIID uint16 = 0x0040
+	// FADF_HAVEVARTYPE the array has a VARTYPE, stored in the 4 bytes before the descriptor
+	FADF_HAVEVARTYPE uint16 = 0x0080
+	// FADF_BSTR the array contains BSTRs
+	FADF_BSTR uint16 = 0x0100
+	// FADF_UNKNOWN the array contains IUnknown interface pointers
+	FADF_UNKNOWN uint16 = 0x0200
+	// FADF_DISPATCH the array contains IDispatch interface pointers
+	FADF_DISPATCH uint16 = 0x0400
+	// FADF_VARIANT the array contains VARIANTs
+	FADF_VARIANT uint16 = 0x0800
+)
+
+// data returns the pvData pointer of the array descriptor
+func (psa *SafeArray) data() unsafe.Pointer {
+	return *(*unsafe.Pointer)(unsafe.Pointer(&psa.pvData))
+}
+
+// bounds returns the bounds of every dimension as they are stored, from the rightmost dimension to the leftmost
+func (psa *SafeArray) bounds() []SafeArrayBound {
+	return unsafe.Slice(&psa.rgsabound[0], psa.cDims)
+}
+
+// SafeArrayBound represents the bounds of one dimension of the array
+//
+//	typedef struct tagSAFEARRAYBOUND {
+//	  ULONG cElements;
+//	  LONG  lLbound;
+//	} SAFEARRAYBOUND, *LPSAFEARRAYBOUND;
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/oaidl/ns-oaidl-safearraybound
+type SafeArrayBound struct {
+	// cElements is the number of elements in the dimension
+	cElements uint32
+	// lLbound is the lowerbound of the dimension
+	lLbound int32
+}
+
+// The build fails here if the size of SAFEARRAYBOUND, the same on every architecture, is not 8 bytes. The
+// architecture dependent layouts are in oaidl_ptr32.go and oaidl_ptr64.go
+func _() {
+	var x [1]struct{}
+	_ = x[unsafe.Sizeof(SafeArrayBound{})-8]
+	_ = x[unsafe.Offsetof(SafeArrayBound{}.lLbound)-4]
+}
+
+// ExcepInfo describes an exception that occurred during IDispatch::Invoke
+// https://docs.microsoft.com/en-us/windows/win32/api/oaidl/ns-oaidl-excepinfo
+type ExcepInfo struct {
+	wCode             uint16
+	wReserved         uint16
+	bstrSource        BSTR
+	bstrDescription   BSTR
+	bstrHelpFile      BSTR
+	dwHelpContext     uint32
+	pvReserved        uintptr
+	pfnDeferredFillIn uintptr
+	scode             uint32
+}
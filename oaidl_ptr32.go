@@ -0,0 +1,100 @@
+//go:build 386 || arm || mips || mipsle
+// +build 386 arm mips mipsle
+
+package clr
+
+import "unsafe"
+
+// Variant is the VARIANT structure on platforms with 32-bit pointers, such as windows/386.
+// The value union is 8 bytes, the size of its largest members: a LONGLONG, a double, a CY or the two pointers of the
+// BRECORD of a VT_RECORD.
+//
+//	typedef struct tagVARIANT {
+//	  VARTYPE vt;
+//	  WORD    wReserved1;
+//	  WORD    wReserved2;
+//	  WORD    wReserved3;
+//	  union {
+//	    LONGLONG llVal;
+//	    ...
+//	    struct {
+//	      PVOID       pvRecord;
+//	      IRecordInfo *pRecInfo;
+//	    } __VARIANT_NAME_4;
+//	  } __VARIANT_NAME_3;
+//	} VARIANT;
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/oaidl/ns-oaidl-variant
+// VARIANT Type Constants https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-oaut/3fe7db9f-5803-4dc4-9d14-5425d3f5461f
+type Variant struct {
+	VT         uint16 // VARTYPE
+	wReserved1 uint16
+	wReserved2 uint16
+	wReserved3 uint16
+	Val        uintptr
+	_          [4]byte
+}
+
+// SafeArray represents a safe array on platforms with 32-bit pointers
+// defined in OAIdl.h
+//
+//	typedef struct tagSAFEARRAY {
+//	  USHORT         cDims;
+//	  USHORT         fFeatures;
+//	  ULONG          cbElements;
+//	  ULONG          cLocks;
+//	  PVOID          pvData;
+//	  SAFEARRAYBOUND rgsabound[1];
+//	} SAFEARRAY;
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/oaidl/ns-oaidl-safearray
+// https://docs.microsoft.com/en-us/archive/msdn-magazine/2017/march/introducing-the-safearray-data-structure
+type SafeArray struct {
+	// cDims is the number of dimensions
+	cDims uint16
+	// fFeatures is the feature flags
+	fFeatures uint16
+	// cbElements is the size of an array element
+	cbElements uint32
+	// cLocks is the number of times the array has been locked without a corresponding unlock
+	cLocks uint32
+	// pvData is the data
+	pvData uintptr
+	// rgsabound is one bound for each dimension. The array continues past its declared length when there is more
+	// than one dimension and the bounds are stored from the last dimension to the first
+	rgsabound [1]SafeArrayBound
+}
+
+// DispParams contains the arguments passed to a method or property through IDispatch::Invoke
+//
+//	typedef struct tagDISPPARAMS {
+//	  VARIANTARG *rgvarg;
+//	  DISPID     *rgdispidNamedArgs;
+//	  UINT       cArgs;
+//	  UINT       cNamedArgs;
+//	} DISPPARAMS;
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/oaidl/ns-oaidl-dispparams
+type DispParams struct {
+	// rgvarg is the array of arguments in reverse order
+	rgvarg *Variant
+	// rgdispidNamedArgs are the dispatch IDs of the named arguments
+	rgdispidNamedArgs *int32
+	// cArgs is the number of arguments
+	cArgs uint32
+	// cNamedArgs is the number of named arguments
+	cNamedArgs uint32
+}
+
+// The build fails here if a size or an offset differs from the native structure
+func _() {
+	var x [1]struct{}
+	_ = x[unsafe.Sizeof(Variant{})-16]
+	_ = x[unsafe.Offsetof(Variant{}.Val)-8]
+	_ = x[unsafe.Sizeof(Decimal{})-16]
+	_ = x[unsafe.Sizeof(SafeArray{})-24]
+	_ = x[unsafe.Offsetof(SafeArray{}.pvData)-12]
+	_ = x[unsafe.Offsetof(SafeArray{}.rgsabound)-16]
+	_ = x[unsafe.Sizeof(DispParams{})-16]
+	_ = x[unsafe.Offsetof(DispParams{}.cArgs)-8]
+}
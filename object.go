@@ -0,0 +1,164 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// instanceMembers are the binding flags used to find the members of an Object
+const instanceMembers = BindingFlags_Public | BindingFlags_Instance
+
+// Object is a handle to a managed object, such as an instance created with Assembly.CreateInstance. It holds a
+// reference on the COM callable wrapper of the object, which keeps the managed object and its state alive across calls
+// until Release is called. Its members are called with late binding through the object's Type
+type Object struct {
+	unknown    *IUnknown
+	objectType *Type
+}
+
+// NewObject returns a handle to the managed object behind an interface pointer, such as an *IUnknown returned by
+// InvokeWithArgs or Object.Call. The Object takes its own reference, the caller still has to release its own
+func NewObject(unknown *IUnknown) (*Object, error) {
+	debugPrint("Entering into object.NewObject()...")
+	if unknown == nil {
+		return nil, fmt.Errorf("the interface pointer is null")
+	}
+	unknown.AddRef()
+	return newObject(unknown)
+}
+
+// newObject returns a handle that takes over the reference held on unknown, it is released if there is an error
+func newObject(unknown *IUnknown) (*Object, error) {
+	objectType, err := unknown.GetType()
+	if err != nil {
+		unknown.Release()
+		return nil, err
+	}
+	return &Object{unknown: unknown, objectType: objectType}, nil
+}
+
+// Unknown returns the interface pointer of the object. The pointer is only valid until the Object is released
+func (obj *Object) Unknown() *IUnknown {
+	if obj == nil {
+		return nil
+	}
+	return obj.unknown
+}
+
+// Type returns the Type of the object. The Type is only valid until the Object is released
+func (obj *Object) Type() *Type {
+	return obj.objectType
+}
+
+// Call invokes the public instance method of the object with the given name, the overload is resolved by the CLR from
+// the types of the arguments. The result is converted with FromVariant, an *IUnknown result holds a reference that
+// must be released by the caller and can be wrapped with NewObject
+func (obj *Object) Call(method string, args ...any) (any, error) {
+	debugPrint("Entering into object.Call()...")
+	return obj.invoke(method, BindingFlags_InvokeMethod, args...)
+}
+
+// GetProperty returns the value of a public instance property of the object, converted as in Call
+func (obj *Object) GetProperty(name string) (any, error) {
+	debugPrint("Entering into object.GetProperty()...")
+	return obj.invoke(name, BindingFlags_GetProperty)
+}
+
+// SetProperty sets the value of a public instance property of the object
+func (obj *Object) SetProperty(name string, value any) error {
+	debugPrint("Entering into object.SetProperty()...")
+	_, err := obj.invoke(name, BindingFlags_SetProperty, value)
+	return err
+}
+
+// GetField returns the value of a public instance field of the object, converted as in Call
+func (obj *Object) GetField(name string) (any, error) {
+	debugPrint("Entering into object.GetField()...")
+	return obj.invoke(name, BindingFlags_GetField)
+}
+
+// SetField sets the value of a public instance field of the object
+func (obj *Object) SetField(name string, value any) error {
+	debugPrint("Entering into object.SetField()...")
+	_, err := obj.invoke(name, BindingFlags_SetField, value)
+	return err
+}
+
+// Release releases the reference held on the managed object, which can then be collected by the CLR. The Object can
+// not be used afterwards
+func (obj *Object) Release() {
+	if obj.objectType != nil {
+		obj.objectType.Release()
+		obj.objectType = nil
+	}
+	if obj.unknown != nil {
+		obj.unknown.Release()
+		obj.unknown = nil
+	}
+}
+
+// invoke calls Type.InvokeMember on the object
+func (obj *Object) invoke(name string, invokeAttr BindingFlags, args ...any) (any, error) {
+	if obj.unknown == nil {
+		return nil, fmt.Errorf("the object was released")
+	}
+	result, err := obj.objectType.InvokeMember(name, invokeAttr|instanceMembers, obj.unknown, args...)
+	if err != nil {
+		typeName, _ := obj.objectType.GetFullName()
+		return nil, fmt.Errorf("there was an error accessing the %s member of %s with (%s):\n%s", name, typeName, argTypes(args), err)
+	}
+	return result, nil
+}
+
+// ObjectHandle is the _ObjectHandle COM interface of the ObjectHandle returned by the AppDomain CreateInstance methods,
+// which wraps the created object so that it is only loaded into the caller's domain when it is unwrapped
+// https://docs.microsoft.com/en-us/dotnet/api/system.runtime.remoting.objecthandle?view=netframework-4.8
+type ObjectHandle struct {
+	vtbl *objectVtbl
+}
+
+func (obj *ObjectHandle) AddRef() uintptr {
+	ret, _, _ := syscall.SyscallN(
+		obj.vtbl.AddRef,
+		uintptr(unsafe.Pointer(obj)),
+	)
+	return ret
+}
+
+func (obj *ObjectHandle) Release() uintptr {
+	ret, _, _ := syscall.SyscallN(
+		obj.vtbl.Release,
+		uintptr(unsafe.Pointer(obj)),
+	)
+	return ret
+}
+
+// Unwrap returns the wrapped object. The ObjectHandle still has to be released
+//
+//	virtual HRESULT __stdcall Unwrap (
+//	/*[out,retval]*/ VARIANT * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.runtime.remoting.objecthandle.unwrap?view=netframework-4.8
+func (obj *ObjectHandle) Unwrap() (*Object, error) {
+	debugPrint("Entering into objecthandle.Unwrap()...")
+	handle := (*IUnknown)(unsafe.Pointer(obj))
+	handleType, err := handle.GetType()
+	if err != nil {
+		return nil, err
+	}
+	defer handleType.Release()
+
+	result, err := handleType.InvokeMember("Unwrap", BindingFlags_InvokeMethod|instanceMembers, handle)
+	if err != nil {
+		return nil, fmt.Errorf("there was an error unwrapping the ObjectHandle:\n%s", err)
+	}
+	unknown, ok := result.(*IUnknown)
+	if !ok || unknown == nil {
+		return nil, fmt.Errorf("the ObjectHandle wraps a %T instead of an object", result)
+	}
+	return newObject(unknown)
+}
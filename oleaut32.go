@@ -0,0 +1,134 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// oleAuto is the real OleAut32.dll on Windows
+var oleAuto oleAutomation = oleaut32{}
+
+var (
+	modOleAut32               = windows.NewLazySystemDLL("OleAut32.dll")
+	procSafeArrayCreate       = modOleAut32.NewProc("SafeArrayCreate")
+	procSafeArrayDestroy      = modOleAut32.NewProc("SafeArrayDestroy")
+	procSafeArrayCopy         = modOleAut32.NewProc("SafeArrayCopy")
+	procSafeArrayLock         = modOleAut32.NewProc("SafeArrayLock")
+	procSafeArrayUnlock       = modOleAut32.NewProc("SafeArrayUnlock")
+	procSafeArrayAccessData   = modOleAut32.NewProc("SafeArrayAccessData")
+	procSafeArrayUnaccessData = modOleAut32.NewProc("SafeArrayUnaccessData")
+	procSafeArrayPutElement   = modOleAut32.NewProc("SafeArrayPutElement")
+	procSafeArrayGetElement   = modOleAut32.NewProc("SafeArrayGetElement")
+	procSafeArrayGetDim       = modOleAut32.NewProc("SafeArrayGetDim")
+	procSafeArrayGetElemsize  = modOleAut32.NewProc("SafeArrayGetElemsize")
+	procSafeArrayGetLBound    = modOleAut32.NewProc("SafeArrayGetLBound")
+	procSafeArrayGetUBound    = modOleAut32.NewProc("SafeArrayGetUBound")
+	procSafeArrayGetVartype   = modOleAut32.NewProc("SafeArrayGetVartype")
+	procSysAllocStringLen     = modOleAut32.NewProc("SysAllocStringLen")
+	procSysFreeString         = modOleAut32.NewProc("SysFreeString")
+	procSysStringLen          = modOleAut32.NewProc("SysStringLen")
+	procVariantClear          = modOleAut32.NewProc("VariantClear")
+	procVariantCopy           = modOleAut32.NewProc("VariantCopy")
+)
+
+// oleaut32 calls the exported functions of OleAut32.dll. The return value of functions that return a pointer is
+// converted by dereferencing its address, Windows returns the pointer in the "ret" value
+type oleaut32 struct{}
+
+func (oleaut32) SafeArrayCreate(vt uint16, cDims uint32, rgsabound *SafeArrayBound) *SafeArray {
+	ret, _, _ := procSafeArrayCreate.Call(uintptr(vt), uintptr(cDims), uintptr(unsafe.Pointer(rgsabound)))
+	return *(**SafeArray)(unsafe.Pointer(&ret))
+}
+
+func (oleaut32) SafeArrayDestroy(psa *SafeArray) uint32 {
+	hr, _, _ := procSafeArrayDestroy.Call(uintptr(unsafe.Pointer(psa)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayCopy(psa *SafeArray, ppsaOut **SafeArray) uint32 {
+	hr, _, _ := procSafeArrayCopy.Call(uintptr(unsafe.Pointer(psa)), uintptr(unsafe.Pointer(ppsaOut)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayLock(psa *SafeArray) uint32 {
+	hr, _, _ := procSafeArrayLock.Call(uintptr(unsafe.Pointer(psa)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayUnlock(psa *SafeArray) uint32 {
+	hr, _, _ := procSafeArrayUnlock.Call(uintptr(unsafe.Pointer(psa)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayAccessData(psa *SafeArray, ppvData *unsafe.Pointer) uint32 {
+	hr, _, _ := procSafeArrayAccessData.Call(uintptr(unsafe.Pointer(psa)), uintptr(unsafe.Pointer(ppvData)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayUnaccessData(psa *SafeArray) uint32 {
+	hr, _, _ := procSafeArrayUnaccessData.Call(uintptr(unsafe.Pointer(psa)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayPutElement(psa *SafeArray, rgIndices *int32, pv unsafe.Pointer) uint32 {
+	hr, _, _ := procSafeArrayPutElement.Call(uintptr(unsafe.Pointer(psa)), uintptr(unsafe.Pointer(rgIndices)), uintptr(pv))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayGetElement(psa *SafeArray, rgIndices *int32, pv unsafe.Pointer) uint32 {
+	hr, _, _ := procSafeArrayGetElement.Call(uintptr(unsafe.Pointer(psa)), uintptr(unsafe.Pointer(rgIndices)), uintptr(pv))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayGetDim(psa *SafeArray) uint32 {
+	ret, _, _ := procSafeArrayGetDim.Call(uintptr(unsafe.Pointer(psa)))
+	return uint32(ret)
+}
+
+func (oleaut32) SafeArrayGetElemsize(psa *SafeArray) uint32 {
+	ret, _, _ := procSafeArrayGetElemsize.Call(uintptr(unsafe.Pointer(psa)))
+	return uint32(ret)
+}
+
+func (oleaut32) SafeArrayGetLBound(psa *SafeArray, nDim uint32, plLbound *int32) uint32 {
+	hr, _, _ := procSafeArrayGetLBound.Call(uintptr(unsafe.Pointer(psa)), uintptr(nDim), uintptr(unsafe.Pointer(plLbound)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayGetUBound(psa *SafeArray, nDim uint32, plUbound *int32) uint32 {
+	hr, _, _ := procSafeArrayGetUBound.Call(uintptr(unsafe.Pointer(psa)), uintptr(nDim), uintptr(unsafe.Pointer(plUbound)))
+	return uint32(hr)
+}
+
+func (oleaut32) SafeArrayGetVartype(psa *SafeArray, pvt *uint16) uint32 {
+	hr, _, _ := procSafeArrayGetVartype.Call(uintptr(unsafe.Pointer(psa)), uintptr(unsafe.Pointer(pvt)))
+	return uint32(hr)
+}
+
+func (oleaut32) SysAllocStringLen(strIn *uint16, ui uint32) unsafe.Pointer {
+	ret, _, _ := procSysAllocStringLen.Call(uintptr(unsafe.Pointer(strIn)), uintptr(ui))
+	return *(*unsafe.Pointer)(unsafe.Pointer(&ret))
+}
+
+func (oleaut32) SysFreeString(bstrString unsafe.Pointer) {
+	procSysFreeString.Call(uintptr(bstrString))
+}
+
+func (oleaut32) SysStringLen(pbstr unsafe.Pointer) uint32 {
+	ret, _, _ := procSysStringLen.Call(uintptr(pbstr))
+	return uint32(ret)
+}
+
+func (oleaut32) VariantClear(pvarg *Variant) uint32 {
+	hr, _, _ := procVariantClear.Call(uintptr(unsafe.Pointer(pvarg)))
+	return uint32(hr)
+}
+
+func (oleaut32) VariantCopy(pvargDest, pvargSrc *Variant) uint32 {
+	hr, _, _ := procVariantCopy.Call(uintptr(unsafe.Pointer(pvargDest)), uintptr(unsafe.Pointer(pvargSrc)))
+	return uint32(hr)
+}
@@ -0,0 +1,495 @@
+package clr
+
+import (
+	"math"
+	"sync"
+	"unsafe"
+)
+
+// safeArrayPrefix is the number of bytes oleaut32 reserves in front of a SAFEARRAY descriptor for the IID of
+// FADF_HAVEIID arrays, or the VARTYPE of FADF_HAVEVARTYPE arrays in its last four bytes
+const safeArrayPrefix = 16
+
+// oleAutomationEmulator is a pure Go implementation of the OleAut32.dll SAFEARRAY, BSTR and VARIANT functions with the
+// same memory layouts: BSTRs are preceded by their length in bytes and followed by a null character, and a SAFEARRAY
+// is a descriptor, preceded by its IID or VARTYPE and followed by its bounds stored from the last dimension to the
+// first, that points to a separate data block. Arrays and strings are allocated from the Go heap and kept alive by
+// the emulator until they are freed
+type oleAutomationEmulator struct {
+	sync.Mutex
+	arrays  map[*SafeArray]*emulatedArray
+	strings map[unsafe.Pointer][]uint32
+}
+
+// emulatedArray holds the memory of a SAFEARRAY created by the emulator
+type emulatedArray struct {
+	descriptor []uint64
+	data       []uint64
+}
+
+// newOleAutomationEmulator returns an emulator with no allocations
+func newOleAutomationEmulator() *oleAutomationEmulator {
+	return &oleAutomationEmulator{
+		arrays:  make(map[*SafeArray]*emulatedArray),
+		strings: make(map[unsafe.Pointer][]uint32),
+	}
+}
+
+func (e *oleAutomationEmulator) SafeArrayCreate(vt uint16, cDims uint32, rgsabound *SafeArrayBound) *SafeArray {
+	e.Lock()
+	defer e.Unlock()
+	return e.create(vt, cDims, rgsabound)
+}
+
+// create allocates a SAFEARRAY with the bounds given from the leftmost dimension to the rightmost
+func (e *oleAutomationEmulator) create(vt uint16, cDims uint32, rgsabound *SafeArrayBound) *SafeArray {
+	size, err := variantElementSize(vt)
+	if err != nil || cDims == 0 || cDims > math.MaxUint16 || rgsabound == nil {
+		return nil
+	}
+	bounds := unsafe.Slice(rgsabound, cDims)
+	total := uint64(size)
+	for _, b := range bounds {
+		total *= uint64(b.cElements)
+		if total > math.MaxInt32 {
+			return nil
+		}
+	}
+
+	header := uint64(unsafe.Offsetof(SafeArray{}.rgsabound)) + uint64(cDims)*uint64(unsafe.Sizeof(SafeArrayBound{}))
+	arr := &emulatedArray{descriptor: make([]uint64, (safeArrayPrefix+header+7)/8)}
+	prefix := unsafe.Pointer(&arr.descriptor[0])
+	psa := (*SafeArray)(unsafe.Add(prefix, safeArrayPrefix))
+	psa.cDims = uint16(cDims)
+	psa.cbElements = uint32(size)
+
+	switch vt {
+	case VT_UNKNOWN, VT_DISPATCH:
+		iid, features := mustKnownGUID("IID_IUnknown"), FADF_UNKNOWN
+		if vt == VT_DISPATCH {
+			iid, features = mustKnownGUID("IID_IDispatch"), FADF_DISPATCH
+		}
+		psa.fFeatures = FADF_HAVEIID | features
+		*(*[16]byte)(prefix) = iid.Bytes()
+	default:
+		psa.fFeatures = FADF_HAVEVARTYPE
+		*(*uint32)(unsafe.Add(prefix, safeArrayPrefix-4)) = uint32(vt)
+		switch vt {
+		case VT_BSTR:
+			psa.fFeatures |= FADF_BSTR
+		case VT_VARIANT:
+			psa.fFeatures |= FADF_VARIANT
+		}
+	}
+
+	stored := psa.bounds()
+	for i, b := range bounds {
+		stored[len(stored)-1-i] = b
+	}
+	if total > 0 {
+		arr.data = make([]uint64, (total+7)/8)
+		*(*unsafe.Pointer)(unsafe.Pointer(&psa.pvData)) = unsafe.Pointer(&arr.data[0])
+	}
+	e.arrays[psa] = arr
+	return psa
+}
+
+func (e *oleAutomationEmulator) SafeArrayDestroy(psa *SafeArray) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	return e.destroy(psa)
+}
+
+// destroy frees the array after releasing every string, VARIANT and interface reference it holds
+func (e *oleAutomationEmulator) destroy(psa *SafeArray) uint32 {
+	if psa == nil {
+		return S_OK
+	}
+	if _, ok := e.arrays[psa]; !ok {
+		return E_INVALIDARG
+	}
+	if psa.cLocks > 0 {
+		return DISP_E_ARRAYISLOCKED
+	}
+	for i, n := 0, e.count(psa); i < n; i++ {
+		if hr := e.clearElement(psa, unsafe.Add(psa.data(), i*int(psa.cbElements))); hr != S_OK {
+			return hr
+		}
+	}
+	delete(e.arrays, psa)
+	return S_OK
+}
+
+// count returns the number of elements in every dimension of the array
+func (e *oleAutomationEmulator) count(psa *SafeArray) int {
+	n := 1
+	for _, b := range psa.bounds() {
+		n *= int(b.cElements)
+	}
+	return n
+}
+
+// clearElement frees the string, VARIANT or interface reference held by one element
+func (e *oleAutomationEmulator) clearElement(psa *SafeArray, p unsafe.Pointer) uint32 {
+	switch {
+	case psa.fFeatures&FADF_BSTR != 0:
+		e.freeString(*(*unsafe.Pointer)(p))
+		*(*unsafe.Pointer)(p) = nil
+	case psa.fFeatures&(FADF_UNKNOWN|FADF_DISPATCH) != 0:
+		if unk := *(*unsafe.Pointer)(p); unk != nil {
+			comRelease(unk)
+		}
+		*(*unsafe.Pointer)(p) = nil
+	case psa.fFeatures&FADF_VARIANT != 0:
+		return e.variantClear((*Variant)(p))
+	}
+	return S_OK
+}
+
+// copyElement copies one element from src to the uninitialized element dst, duplicating strings and VARIANTs and
+// adding a reference to interface pointers
+func (e *oleAutomationEmulator) copyElement(psa *SafeArray, dst, src unsafe.Pointer) uint32 {
+	switch {
+	case psa.fFeatures&FADF_BSTR != 0:
+		*(*unsafe.Pointer)(dst) = e.copyString(*(*unsafe.Pointer)(src))
+	case psa.fFeatures&(FADF_UNKNOWN|FADF_DISPATCH) != 0:
+		unk := *(*unsafe.Pointer)(src)
+		if unk != nil {
+			comAddRef(unk)
+		}
+		*(*unsafe.Pointer)(dst) = unk
+	case psa.fFeatures&FADF_VARIANT != 0:
+		*(*Variant)(dst) = Variant{}
+		return e.variantCopy((*Variant)(dst), (*Variant)(src))
+	default:
+		copy(unsafe.Slice((*byte)(dst), psa.cbElements), unsafe.Slice((*byte)(src), psa.cbElements))
+	}
+	return S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayCopy(psa *SafeArray, ppsaOut **SafeArray) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	if ppsaOut == nil {
+		return E_INVALIDARG
+	}
+	*ppsaOut = nil
+	out, hr := e.copyArray(psa)
+	*ppsaOut = out
+	return hr
+}
+
+// copyArray creates a new array with the same type and bounds as psa and copies every element into it
+func (e *oleAutomationEmulator) copyArray(psa *SafeArray) (*SafeArray, uint32) {
+	if psa == nil {
+		return nil, S_OK
+	}
+	if _, ok := e.arrays[psa]; !ok {
+		return nil, E_INVALIDARG
+	}
+	var vt uint16
+	if hr := e.vartype(psa, &vt); hr != S_OK {
+		return nil, hr
+	}
+	stored := psa.bounds()
+	bounds := make([]SafeArrayBound, len(stored))
+	for i, b := range stored {
+		bounds[len(bounds)-1-i] = b
+	}
+	out := e.create(vt, uint32(len(bounds)), &bounds[0])
+	if out == nil {
+		return nil, E_OUTOFMEMORY
+	}
+	for i, n := 0, e.count(psa); i < n; i++ {
+		offset := i * int(psa.cbElements)
+		if hr := e.copyElement(psa, unsafe.Add(out.data(), offset), unsafe.Add(psa.data(), offset)); hr != S_OK {
+			e.destroy(out)
+			return nil, hr
+		}
+	}
+	return out, S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayLock(psa *SafeArray) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	if _, ok := e.arrays[psa]; !ok {
+		return E_INVALIDARG
+	}
+	psa.cLocks++
+	return S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayUnlock(psa *SafeArray) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	if _, ok := e.arrays[psa]; !ok {
+		return E_INVALIDARG
+	}
+	if psa.cLocks == 0 {
+		return E_UNEXPECTED
+	}
+	psa.cLocks--
+	return S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayAccessData(psa *SafeArray, ppvData *unsafe.Pointer) uint32 {
+	if ppvData == nil {
+		return E_INVALIDARG
+	}
+	if hr := e.SafeArrayLock(psa); hr != S_OK {
+		return hr
+	}
+	*ppvData = psa.data()
+	return S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayUnaccessData(psa *SafeArray) uint32 {
+	return e.SafeArrayUnlock(psa)
+}
+
+// element returns the address of the element at rgIndices, which holds one index for each dimension from the
+// leftmost to the rightmost. The leftmost index changes fastest in the data block
+func (e *oleAutomationEmulator) element(psa *SafeArray, rgIndices *int32) (unsafe.Pointer, uint32) {
+	if _, ok := e.arrays[psa]; !ok || rgIndices == nil {
+		return nil, E_INVALIDARG
+	}
+	indices := unsafe.Slice(rgIndices, psa.cDims)
+	stored := psa.bounds()
+	offset, stride := 0, 1
+	for d, index := range indices {
+		b := stored[len(stored)-1-d]
+		i := int64(index) - int64(b.lLbound)
+		if i < 0 || i >= int64(b.cElements) {
+			return nil, DISP_E_BADINDEX
+		}
+		offset += int(i) * stride
+		stride *= int(b.cElements)
+	}
+	return unsafe.Add(psa.data(), offset*int(psa.cbElements)), S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayPutElement(psa *SafeArray, rgIndices *int32, pv unsafe.Pointer) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	p, hr := e.element(psa, rgIndices)
+	if hr != S_OK {
+		return hr
+	}
+	switch {
+	case psa.fFeatures&FADF_BSTR != 0:
+		old := *(*unsafe.Pointer)(p)
+		*(*unsafe.Pointer)(p) = e.copyString(pv)
+		e.freeString(old)
+	case psa.fFeatures&(FADF_UNKNOWN|FADF_DISPATCH) != 0:
+		if pv != nil {
+			comAddRef(pv)
+		}
+		old := *(*unsafe.Pointer)(p)
+		*(*unsafe.Pointer)(p) = pv
+		if old != nil {
+			comRelease(old)
+		}
+	case pv == nil:
+		return E_INVALIDARG
+	case psa.fFeatures&FADF_VARIANT != 0:
+		return e.variantCopy((*Variant)(p), (*Variant)(pv))
+	default:
+		copy(unsafe.Slice((*byte)(p), psa.cbElements), unsafe.Slice((*byte)(pv), psa.cbElements))
+	}
+	return S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayGetElement(psa *SafeArray, rgIndices *int32, pv unsafe.Pointer) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	p, hr := e.element(psa, rgIndices)
+	if hr != S_OK {
+		return hr
+	}
+	if pv == nil {
+		return E_INVALIDARG
+	}
+	return e.copyElement(psa, pv, p)
+}
+
+func (e *oleAutomationEmulator) SafeArrayGetDim(psa *SafeArray) uint32 {
+	if psa == nil {
+		return 0
+	}
+	return uint32(psa.cDims)
+}
+
+func (e *oleAutomationEmulator) SafeArrayGetElemsize(psa *SafeArray) uint32 {
+	if psa == nil {
+		return 0
+	}
+	return psa.cbElements
+}
+
+// bound returns the bound of dimension nDim, counted from 1 for the leftmost dimension
+func (e *oleAutomationEmulator) bound(psa *SafeArray, nDim uint32) (SafeArrayBound, uint32) {
+	e.Lock()
+	defer e.Unlock()
+	if _, ok := e.arrays[psa]; !ok {
+		return SafeArrayBound{}, E_INVALIDARG
+	}
+	if nDim == 0 || nDim > uint32(psa.cDims) {
+		return SafeArrayBound{}, DISP_E_BADINDEX
+	}
+	return psa.bounds()[uint32(psa.cDims)-nDim], S_OK
+}
+
+func (e *oleAutomationEmulator) SafeArrayGetLBound(psa *SafeArray, nDim uint32, plLbound *int32) uint32 {
+	b, hr := e.bound(psa, nDim)
+	if hr == S_OK && plLbound != nil {
+		*plLbound = b.lLbound
+	}
+	return hr
+}
+
+func (e *oleAutomationEmulator) SafeArrayGetUBound(psa *SafeArray, nDim uint32, plUbound *int32) uint32 {
+	b, hr := e.bound(psa, nDim)
+	if hr == S_OK && plUbound != nil {
+		*plUbound = b.lLbound + int32(b.cElements) - 1
+	}
+	return hr
+}
+
+func (e *oleAutomationEmulator) SafeArrayGetVartype(psa *SafeArray, pvt *uint16) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	if _, ok := e.arrays[psa]; !ok || pvt == nil {
+		return E_INVALIDARG
+	}
+	return e.vartype(psa, pvt)
+}
+
+// vartype reads the VARTYPE of the array from the feature flags or from the four bytes before the descriptor
+func (e *oleAutomationEmulator) vartype(psa *SafeArray, pvt *uint16) uint32 {
+	switch {
+	case psa.fFeatures&FADF_HAVEVARTYPE != 0:
+		*pvt = uint16(*(*uint32)(unsafe.Add(unsafe.Pointer(psa), -4)))
+	case psa.fFeatures&FADF_DISPATCH != 0:
+		*pvt = VT_DISPATCH
+	case psa.fFeatures&FADF_UNKNOWN != 0:
+		*pvt = VT_UNKNOWN
+	default:
+		return E_INVALIDARG
+	}
+	return S_OK
+}
+
+func (e *oleAutomationEmulator) SysAllocStringLen(strIn *uint16, ui uint32) unsafe.Pointer {
+	e.Lock()
+	defer e.Unlock()
+	var s []uint16
+	if strIn != nil {
+		s = unsafe.Slice(strIn, ui)
+	}
+	return e.allocString(s, ui)
+}
+
+// allocString allocates a BSTR of n characters, copied from s when it is not nil, followed by a null character
+func (e *oleAutomationEmulator) allocString(s []uint16, n uint32) unsafe.Pointer {
+	// The length prefix is a 32-bit word and the characters start right after it
+	buf := make([]uint32, 1+(uint64(n)+2)/2)
+	buf[0] = n * 2
+	bstr := unsafe.Pointer(&buf[1])
+	copy(unsafe.Slice((*uint16)(bstr), n), s)
+	e.strings[bstr] = buf
+	return bstr
+}
+
+// copyString allocates a copy of a BSTR with the same length
+func (e *oleAutomationEmulator) copyString(bstr unsafe.Pointer) unsafe.Pointer {
+	if bstr == nil {
+		return nil
+	}
+	n := *(*uint32)(unsafe.Add(bstr, -4)) / 2
+	return e.allocString(unsafe.Slice((*uint16)(bstr), n), n)
+}
+
+func (e *oleAutomationEmulator) SysFreeString(bstrString unsafe.Pointer) {
+	e.Lock()
+	defer e.Unlock()
+	e.freeString(bstrString)
+}
+
+// freeString drops the emulator's reference to a BSTR so the garbage collector can reclaim it
+func (e *oleAutomationEmulator) freeString(bstr unsafe.Pointer) {
+	delete(e.strings, bstr)
+}
+
+func (e *oleAutomationEmulator) SysStringLen(pbstr unsafe.Pointer) uint32 {
+	if pbstr == nil {
+		return 0
+	}
+	return *(*uint32)(unsafe.Add(pbstr, -4)) / 2
+}
+
+func (e *oleAutomationEmulator) VariantClear(pvarg *Variant) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	return e.variantClear(pvarg)
+}
+
+// variantClear frees the string, array or interface reference held by the VARIANT and sets it to VT_EMPTY
+func (e *oleAutomationEmulator) variantClear(pvarg *Variant) uint32 {
+	if pvarg == nil {
+		return E_INVALIDARG
+	}
+	switch {
+	case pvarg.VT&VT_BYREF != 0:
+	case pvarg.VT&VT_ARRAY != 0:
+		if hr := e.destroy((*SafeArray)(pvarg.ptr())); hr != S_OK {
+			return hr
+		}
+	case pvarg.VT == VT_BSTR:
+		e.freeString(pvarg.ptr())
+	case pvarg.VT == VT_UNKNOWN || pvarg.VT == VT_DISPATCH:
+		if unk := pvarg.ptr(); unk != nil {
+			comRelease(unk)
+		}
+	}
+	pvarg.VT = VT_EMPTY
+	return S_OK
+}
+
+func (e *oleAutomationEmulator) VariantCopy(pvargDest, pvargSrc *Variant) uint32 {
+	e.Lock()
+	defer e.Unlock()
+	return e.variantCopy(pvargDest, pvargSrc)
+}
+
+// variantCopy clears the destination and copies the source into it, duplicating strings and arrays and adding a
+// reference to interface pointers. VT_BYREF VARIANTs are copied as they are
+func (e *oleAutomationEmulator) variantCopy(pvargDest, pvargSrc *Variant) uint32 {
+	if pvargDest == nil || pvargSrc == nil {
+		return E_INVALIDARG
+	}
+	if pvargDest == pvargSrc {
+		return S_OK
+	}
+	if hr := e.variantClear(pvargDest); hr != S_OK {
+		return hr
+	}
+	src := *pvargSrc
+	switch {
+	case src.VT&VT_BYREF != 0:
+	case src.VT&VT_ARRAY != 0:
+		out, hr := e.copyArray((*SafeArray)(src.ptr()))
+		if hr != S_OK {
+			return hr
+		}
+		src.setPtr(unsafe.Pointer(out))
+	case src.VT == VT_BSTR:
+		src.setPtr(e.copyString(src.ptr()))
+	case src.VT == VT_UNKNOWN || src.VT == VT_DISPATCH:
+		if unk := src.ptr(); unk != nil {
+			comAddRef(unk)
+		}
+	}
+	*pvargDest = src
+	return S_OK
+}
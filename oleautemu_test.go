@@ -0,0 +1,378 @@
+package clr
+
+import (
+	"testing"
+	"unicode/utf16"
+	"unsafe"
+)
+
+// emulatedString allocates a BSTR holding s with the emulator
+func emulatedString(e *oleAutomationEmulator, s string) unsafe.Pointer {
+	u := utf16.Encode([]rune(s))
+	var p *uint16
+	if len(u) > 0 {
+		p = &u[0]
+	}
+	return e.SysAllocStringLen(p, uint32(len(u)))
+}
+
+func TestEmulatorSafeArrayOneDimension(t *testing.T) {
+	e := newOleAutomationEmulator()
+	bound := NewSafeArrayBound(-2, 5)
+	psa := e.SafeArrayCreate(VT_I4, 1, &bound)
+	if psa == nil {
+		t.Fatal("SafeArrayCreate returned NULL")
+	}
+	if dims, size := e.SafeArrayGetDim(psa), e.SafeArrayGetElemsize(psa); dims != 1 || size != 4 {
+		t.Errorf("the array has %d dimensions of %d byte elements, want 1 and 4", dims, size)
+	}
+	var lower, upper int32
+	if e.SafeArrayGetLBound(psa, 1, &lower) != S_OK || e.SafeArrayGetUBound(psa, 1, &upper) != S_OK || lower != -2 || upper != 2 {
+		t.Errorf("the bounds are %d to %d, want -2 to 2", lower, upper)
+	}
+	if hr := e.SafeArrayGetLBound(psa, 2, &lower); hr != DISP_E_BADINDEX {
+		t.Errorf("SafeArrayGetLBound of the second dimension returned 0x%x, want DISP_E_BADINDEX", hr)
+	}
+	var vt uint16
+	if hr := e.SafeArrayGetVartype(psa, &vt); hr != S_OK || vt != VT_I4 {
+		t.Errorf("SafeArrayGetVartype returned 0x%x and 0x%x, want VT_I4", hr, vt)
+	}
+
+	for i := int32(-2); i <= 2; i++ {
+		value := i * 100
+		if hr := e.SafeArrayPutElement(psa, &i, unsafe.Pointer(&value)); hr != S_OK {
+			t.Fatalf("SafeArrayPutElement(%d) returned 0x%x", i, hr)
+		}
+	}
+	for i := int32(-2); i <= 2; i++ {
+		var value int32
+		if hr := e.SafeArrayGetElement(psa, &i, unsafe.Pointer(&value)); hr != S_OK || value != i*100 {
+			t.Errorf("SafeArrayGetElement(%d) returned 0x%x and %d, want %d", i, hr, value, i*100)
+		}
+	}
+	for _, i := range []int32{-3, 3} {
+		var value int32
+		if hr := e.SafeArrayGetElement(psa, &i, unsafe.Pointer(&value)); hr != DISP_E_BADINDEX {
+			t.Errorf("SafeArrayGetElement(%d) returned 0x%x, want DISP_E_BADINDEX", i, hr)
+		}
+		if hr := e.SafeArrayPutElement(psa, &i, unsafe.Pointer(&value)); hr != DISP_E_BADINDEX {
+			t.Errorf("SafeArrayPutElement(%d) returned 0x%x, want DISP_E_BADINDEX", i, hr)
+		}
+	}
+
+	if hr := e.SafeArrayDestroy(psa); hr != S_OK {
+		t.Errorf("SafeArrayDestroy returned 0x%x", hr)
+	}
+	if len(e.arrays) != 0 {
+		t.Errorf("%d arrays are still allocated", len(e.arrays))
+	}
+}
+
+func TestEmulatorSafeArrayDimensions(t *testing.T) {
+	e := newOleAutomationEmulator()
+	// Bounds are given from the leftmost dimension to the rightmost
+	bounds := []SafeArrayBound{NewSafeArrayBound(0, 2), NewSafeArrayBound(1, 3), NewSafeArrayBound(-1, 4)}
+	psa := e.SafeArrayCreate(VT_I2, uint32(len(bounds)), &bounds[0])
+	if psa == nil {
+		t.Fatal("SafeArrayCreate returned NULL")
+	}
+	defer e.SafeArrayDestroy(psa)
+
+	for d, b := range bounds {
+		var lower, upper int32
+		e.SafeArrayGetLBound(psa, uint32(d+1), &lower)
+		e.SafeArrayGetUBound(psa, uint32(d+1), &upper)
+		if lower != b.lLbound || upper != b.lLbound+int32(b.cElements)-1 {
+			t.Errorf("dimension %d has the bounds %d to %d", d+1, lower, upper)
+		}
+	}
+
+	index := func(i, j, k int32) int16 { return int16(i*100 + j*10 + k) }
+	for i := int32(0); i < 2; i++ {
+		for j := int32(1); j < 4; j++ {
+			for k := int32(-1); k < 3; k++ {
+				indices := []int32{i, j, k}
+				value := index(i, j, k)
+				if hr := e.SafeArrayPutElement(psa, &indices[0], unsafe.Pointer(&value)); hr != S_OK {
+					t.Fatalf("SafeArrayPutElement(%v) returned 0x%x", indices, hr)
+				}
+			}
+		}
+	}
+
+	var data unsafe.Pointer
+	if hr := e.SafeArrayAccessData(psa, &data); hr != S_OK {
+		t.Fatalf("SafeArrayAccessData returned 0x%x", hr)
+	}
+	elements := unsafe.Slice((*int16)(data), 2*3*4)
+	for i := int32(0); i < 2; i++ {
+		for j := int32(1); j < 4; j++ {
+			for k := int32(-1); k < 3; k++ {
+				// The leftmost index changes fastest
+				offset := i + 2*(j-1) + 6*(k+1)
+				if got := elements[offset]; got != index(i, j, k) {
+					t.Errorf("the element [%d, %d, %d] at offset %d is %d", i, j, k, offset, got)
+				}
+				indices := []int32{i, j, k}
+				var value int16
+				if hr := e.SafeArrayGetElement(psa, &indices[0], unsafe.Pointer(&value)); hr != S_OK || value != index(i, j, k) {
+					t.Errorf("SafeArrayGetElement(%v) returned 0x%x and %d", indices, hr, value)
+				}
+			}
+		}
+	}
+	e.SafeArrayUnaccessData(psa)
+
+	indices := []int32{0, 0, 0}
+	var value int16
+	if hr := e.SafeArrayGetElement(psa, &indices[0], unsafe.Pointer(&value)); hr != DISP_E_BADINDEX {
+		t.Errorf("SafeArrayGetElement(%v) returned 0x%x, want DISP_E_BADINDEX", indices, hr)
+	}
+}
+
+func TestEmulatorSafeArrayStrings(t *testing.T) {
+	e := newOleAutomationEmulator()
+	bound := NewSafeArrayBound(0, 2)
+	psa := e.SafeArrayCreate(VT_BSTR, 1, &bound)
+	if psa == nil {
+		t.Fatal("SafeArrayCreate returned NULL")
+	}
+	if psa.fFeatures&FADF_BSTR == 0 {
+		t.Errorf("the features 0x%x of an array of strings don't have FADF_BSTR", psa.fFeatures)
+	}
+
+	str := emulatedString(e, "Seatbelt")
+	index := int32(1)
+	if hr := e.SafeArrayPutElement(psa, &index, str); hr != S_OK {
+		t.Fatalf("SafeArrayPutElement returned 0x%x", hr)
+	}
+	e.SysFreeString(str)
+
+	var got unsafe.Pointer
+	if hr := e.SafeArrayGetElement(psa, &index, unsafe.Pointer(&got)); hr != S_OK {
+		t.Fatalf("SafeArrayGetElement returned 0x%x", hr)
+	}
+	if s := (BSTR{got}).String(); s != "Seatbelt" {
+		t.Errorf("the element is %q", s)
+	}
+	e.SysFreeString(got)
+
+	index = 0
+	if hr := e.SafeArrayGetElement(psa, &index, unsafe.Pointer(&got)); hr != S_OK || got != nil {
+		t.Errorf("the unset element returned 0x%x and %p, want a NULL BSTR", hr, got)
+	}
+
+	var copied *SafeArray
+	if hr := e.SafeArrayCopy(psa, &copied); hr != S_OK {
+		t.Fatalf("SafeArrayCopy returned 0x%x", hr)
+	}
+	if len(e.strings) != 2 {
+		t.Errorf("%d strings are allocated, want the one of the array and of its copy", len(e.strings))
+	}
+	e.SafeArrayDestroy(psa)
+	e.SafeArrayDestroy(copied)
+	if len(e.strings) != 0 || len(e.arrays) != 0 {
+		t.Errorf("%d strings and %d arrays are still allocated", len(e.strings), len(e.arrays))
+	}
+}
+
+func TestEmulatorSafeArrayLocks(t *testing.T) {
+	e := newOleAutomationEmulator()
+	bound := NewSafeArrayBound(0, 1)
+	psa := e.SafeArrayCreate(VT_UI1, 1, &bound)
+
+	if hr := e.SafeArrayLock(psa); hr != S_OK {
+		t.Fatalf("SafeArrayLock returned 0x%x", hr)
+	}
+	var data unsafe.Pointer
+	if hr := e.SafeArrayAccessData(psa, &data); hr != S_OK {
+		t.Fatalf("SafeArrayAccessData returned 0x%x", hr)
+	}
+	if psa.cLocks != 2 {
+		t.Errorf("the lock count is %d, want 2", psa.cLocks)
+	}
+	if hr := e.SafeArrayDestroy(psa); hr != DISP_E_ARRAYISLOCKED {
+		t.Errorf("SafeArrayDestroy of a locked array returned 0x%x, want DISP_E_ARRAYISLOCKED", hr)
+	}
+	v := Variant{VT: VT_ARRAY | VT_UI1}
+	v.setPtr(unsafe.Pointer(psa))
+	if hr := e.VariantClear(&v); hr != DISP_E_ARRAYISLOCKED || v.VT != VT_ARRAY|VT_UI1 {
+		t.Errorf("VariantClear of a locked array returned 0x%x and the type 0x%x", hr, v.VT)
+	}
+
+	e.SafeArrayUnaccessData(psa)
+	if hr := e.SafeArrayDestroy(psa); hr != DISP_E_ARRAYISLOCKED {
+		t.Errorf("SafeArrayDestroy of an array locked once returned 0x%x, want DISP_E_ARRAYISLOCKED", hr)
+	}
+	e.SafeArrayUnlock(psa)
+	if hr := e.SafeArrayUnlock(psa); hr != E_UNEXPECTED {
+		t.Errorf("SafeArrayUnlock of an unlocked array returned 0x%x, want E_UNEXPECTED", hr)
+	}
+	if hr := e.SafeArrayDestroy(psa); hr != S_OK {
+		t.Errorf("SafeArrayDestroy returned 0x%x", hr)
+	}
+	if hr := e.SafeArrayDestroy(psa); hr != E_INVALIDARG {
+		t.Errorf("SafeArrayDestroy of a destroyed array returned 0x%x, want E_INVALIDARG", hr)
+	}
+	if hr := e.SafeArrayLock(psa); hr != E_INVALIDARG {
+		t.Errorf("SafeArrayLock of a destroyed array returned 0x%x, want E_INVALIDARG", hr)
+	}
+}
+
+func TestEmulatorStrings(t *testing.T) {
+	e := newOleAutomationEmulator()
+	str := emulatedString(e, "a\x00bé")
+	if n := e.SysStringLen(str); n != 4 {
+		t.Errorf("SysStringLen returned %d, want 4", n)
+	}
+	if prefix := *(*uint32)(unsafe.Add(str, -4)); prefix != 8 {
+		t.Errorf("the length prefix is %d bytes, want 8", prefix)
+	}
+	if terminator := *(*uint16)(unsafe.Add(str, 8)); terminator != 0 {
+		t.Errorf("the string is terminated by 0x%x", terminator)
+	}
+	if s := (BSTR{str}).String(); s != "a\x00bé" {
+		t.Errorf("the string is %q", s)
+	}
+
+	blank := e.SysAllocStringLen(nil, 3)
+	if n := e.SysStringLen(blank); n != 3 {
+		t.Errorf("SysStringLen of an uninitialized string returned %d, want 3", n)
+	}
+	if n := e.SysStringLen(nil); n != 0 {
+		t.Errorf("SysStringLen of a NULL BSTR returned %d", n)
+	}
+
+	e.SysFreeString(str)
+	e.SysFreeString(blank)
+	e.SysFreeString(nil)
+	if len(e.strings) != 0 {
+		t.Errorf("%d strings are still allocated", len(e.strings))
+	}
+}
+
+func TestBSTR(t *testing.T) {
+	bstr, err := SysAllocString("tool\x00.exe")
+	if err != nil {
+		t.Fatalf("SysAllocString returned an error: %s", err)
+	}
+	if n := bstr.Len(); n != 9 {
+		t.Errorf("Len returned %d, want 9", n)
+	}
+	if s := bstr.String(); s != "tool\x00.exe" {
+		t.Errorf("String returned %q", s)
+	}
+	bstr.Free()
+	if bstr.Pointer() != nil || bstr.Len() != 0 || bstr.String() != "" {
+		t.Errorf("the freed BSTR is not NULL")
+	}
+	bstr.Free()
+}
+
+func TestEmulatorVariantClear(t *testing.T) {
+	e := newOleAutomationEmulator()
+
+	str := Variant{VT: VT_BSTR}
+	str.setPtr(emulatedString(e, "value"))
+	if hr := e.VariantClear(&str); hr != S_OK || str.VT != VT_EMPTY {
+		t.Errorf("VariantClear of a BSTR returned 0x%x and the type 0x%x", hr, str.VT)
+	}
+	if len(e.strings) != 0 {
+		t.Errorf("the BSTR of the VARIANT was not freed")
+	}
+
+	bound := NewSafeArrayBound(0, 1)
+	psa := e.SafeArrayCreate(VT_VARIANT, 1, &bound)
+	element := Variant{VT: VT_BSTR}
+	element.setPtr(emulatedString(e, "element"))
+	index := int32(0)
+	e.SafeArrayPutElement(psa, &index, unsafe.Pointer(&element))
+	e.VariantClear(&element)
+	array := Variant{VT: VT_ARRAY | VT_VARIANT}
+	array.setPtr(unsafe.Pointer(psa))
+	if hr := e.VariantClear(&array); hr != S_OK || array.VT != VT_EMPTY {
+		t.Errorf("VariantClear of an array returned 0x%x and the type 0x%x", hr, array.VT)
+	}
+	if len(e.arrays) != 0 || len(e.strings) != 0 {
+		t.Errorf("%d arrays and %d strings of the VARIANT were not freed", len(e.arrays), len(e.strings))
+	}
+
+	obj, err := NewDispatchObject(&emulatorReceiver{})
+	if err != nil {
+		t.Fatalf("NewDispatchObject returned an error: %s", err)
+	}
+	defer obj.Release()
+	unknown := obj.Variant()
+	if refs := obj.RefCount(); refs != 2 {
+		t.Fatalf("the VARIANT holds %d references, want 2", refs)
+	}
+	var copied Variant
+	if hr := e.VariantCopy(&copied, &unknown); hr != S_OK || copied.VT != VT_DISPATCH || obj.RefCount() != 3 {
+		t.Errorf("VariantCopy returned 0x%x and the type 0x%x and the object has %d references", hr, copied.VT, obj.RefCount())
+	}
+	e.VariantClear(&copied)
+	if hr := e.VariantClear(&unknown); hr != S_OK || unknown.VT != VT_EMPTY {
+		t.Errorf("VariantClear of an interface returned 0x%x and the type 0x%x", hr, unknown.VT)
+	}
+	if refs := obj.RefCount(); refs != 1 {
+		t.Errorf("the object has %d references after clearing the VARIANTs, want 1", refs)
+	}
+
+	// A VT_BYREF VARIANT doesn't own what it points to
+	value := int32(7)
+	byRef := Variant{VT: VT_I4 | VT_BYREF}
+	byRef.setPtr(unsafe.Pointer(&value))
+	if hr := e.VariantClear(&byRef); hr != S_OK || byRef.VT != VT_EMPTY || value != 7 {
+		t.Errorf("VariantClear of a VT_BYREF returned 0x%x and the type 0x%x", hr, byRef.VT)
+	}
+	if hr := e.VariantClear(nil); hr != E_INVALIDARG {
+		t.Errorf("VariantClear(nil) returned 0x%x, want E_INVALIDARG", hr)
+	}
+}
+
+func TestPrepareParameters(t *testing.T) {
+	emulator, emulated := oleAuto.(*oleAutomationEmulator)
+	var arrays, strings int
+	if emulated {
+		arrays, strings = len(emulator.arrays), len(emulator.strings)
+	}
+
+	for _, params := range [][]string{{"-group=system", "-full", ""}, {}} {
+		psa, err := PrepareParameters(params)
+		if err != nil {
+			t.Fatalf("PrepareParameters(%q) returned an error: %s", params, err)
+		}
+		args, err := WrapSafeArray[any](psa)
+		if err != nil {
+			t.Fatalf("the parameters are not an array of VARIANTs: %s", err)
+		}
+		if n, _ := args.Len(); n != 1 {
+			t.Errorf("the parameters hold %d arguments, want the string array of Main", n)
+		}
+		arg, err := args.Get(0)
+		if err != nil {
+			t.Fatalf("Get(0) returned an error: %s", err)
+		}
+		got, ok := arg.([]string)
+		if !ok || len(got) != len(params) {
+			t.Fatalf("the argument is %#v, want %q", arg, params)
+		}
+		for i := range params {
+			if got[i] != params[i] {
+				t.Errorf("the argument %d is %q, want %q", i, got[i], params[i])
+			}
+		}
+		if err = args.Destroy(); err != nil {
+			t.Errorf("Destroy returned an error: %s", err)
+		}
+	}
+
+	if emulated && (len(emulator.arrays) != arrays || len(emulator.strings) != strings) {
+		t.Errorf("PrepareParameters leaked %d arrays and %d strings", len(emulator.arrays)-arrays, len(emulator.strings)-strings)
+	}
+}
+
+// emulatorReceiver is exposed through IDispatch to get an interface pointer
+type emulatorReceiver struct{}
+
+func (emulatorReceiver) Ping() string { return "pong" }
@@ -0,0 +1,148 @@
+package clr
+
+import (
+	"fmt"
+	"io/fs"
+	"strings"
+)
+
+// BindingFlags controls how members are searched for and invoked by reflection
+//
+//	enum BindingFlags
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.bindingflags?view=net-5.0
+type BindingFlags int32
+
+const (
+	BindingFlags_Default              BindingFlags = 0x00000000
+	BindingFlags_IgnoreCase           BindingFlags = 0x00000001
+	BindingFlags_DeclaredOnly         BindingFlags = 0x00000002
+	BindingFlags_Instance             BindingFlags = 0x00000004
+	BindingFlags_Static               BindingFlags = 0x00000008
+	BindingFlags_Public               BindingFlags = 0x00000010
+	BindingFlags_NonPublic            BindingFlags = 0x00000020
+	BindingFlags_FlattenHierarchy     BindingFlags = 0x00000040
+	BindingFlags_InvokeMethod         BindingFlags = 0x00000100
+	BindingFlags_CreateInstance       BindingFlags = 0x00000200
+	BindingFlags_GetField             BindingFlags = 0x00000400
+	BindingFlags_SetField             BindingFlags = 0x00000800
+	BindingFlags_GetProperty          BindingFlags = 0x00001000
+	BindingFlags_SetProperty          BindingFlags = 0x00002000
+	BindingFlags_PutDispProperty      BindingFlags = 0x00004000
+	BindingFlags_PutRefDispProperty   BindingFlags = 0x00008000
+	BindingFlags_ExactBinding         BindingFlags = 0x00010000
+	BindingFlags_SuppressChangeType   BindingFlags = 0x00020000
+	BindingFlags_OptionalParamBinding BindingFlags = 0x00040000
+	BindingFlags_IgnoreReturn         BindingFlags = 0x01000000
+)
+
+// MemberTypes is the kind of a member returned by reflection
+//
+//	enum MemberTypes
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.membertypes?view=net-5.0
+type MemberTypes int32
+
+const (
+	MemberTypes_Constructor MemberTypes = 0x01
+	MemberTypes_Event       MemberTypes = 0x02
+	MemberTypes_Field       MemberTypes = 0x04
+	MemberTypes_Method      MemberTypes = 0x08
+	MemberTypes_Property    MemberTypes = 0x10
+	MemberTypes_TypeInfo    MemberTypes = 0x20
+	MemberTypes_Custom      MemberTypes = 0x40
+	MemberTypes_NestedType  MemberTypes = 0x80
+	MemberTypes_All         MemberTypes = 0xbf
+)
+
+// MethodAttributes are the flags of a method, its accessibility in the MethodAttributes_MemberAccessMask bits and
+// whether it is static, virtual or abstract
+//
+//	enum MethodAttributes
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.methodattributes?view=net-5.0
+type MethodAttributes int32
+
+const (
+	MethodAttributes_MemberAccessMask      MethodAttributes = 0x0007
+	MethodAttributes_PrivateScope          MethodAttributes = 0x0000
+	MethodAttributes_Private               MethodAttributes = 0x0001
+	MethodAttributes_FamANDAssem           MethodAttributes = 0x0002
+	MethodAttributes_Assembly              MethodAttributes = 0x0003
+	MethodAttributes_Family                MethodAttributes = 0x0004
+	MethodAttributes_FamORAssem            MethodAttributes = 0x0005
+	MethodAttributes_Public                MethodAttributes = 0x0006
+	MethodAttributes_UnmanagedExport       MethodAttributes = 0x0008
+	MethodAttributes_Static                MethodAttributes = 0x0010
+	MethodAttributes_Final                 MethodAttributes = 0x0020
+	MethodAttributes_Virtual               MethodAttributes = 0x0040
+	MethodAttributes_HideBySig             MethodAttributes = 0x0080
+	MethodAttributes_NewSlot               MethodAttributes = 0x0100
+	MethodAttributes_CheckAccessOnOverride MethodAttributes = 0x0200
+	MethodAttributes_Abstract              MethodAttributes = 0x0400
+	MethodAttributes_SpecialName           MethodAttributes = 0x0800
+	MethodAttributes_RTSpecialName         MethodAttributes = 0x1000
+	MethodAttributes_PinvokeImpl           MethodAttributes = 0x2000
+	MethodAttributes_HasSecurity           MethodAttributes = 0x4000
+	MethodAttributes_RequireSecObject      MethodAttributes = 0x8000
+)
+
+// TypeLoadError is the ReflectionTypeLoadException thrown when some of the types of an assembly could not be loaded,
+// usually because an assembly they depend on is missing. Assembly.GetTypes returns it along with the types that did load
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.reflectiontypeloadexception?view=net-5.0
+type TypeLoadError struct {
+	// LoaderExceptions holds the message of each exception thrown by the class loader
+	LoaderExceptions []string
+}
+
+func (e *TypeLoadError) Error() string {
+	if len(e.LoaderExceptions) == 0 {
+		return "unable to load one or more of the requested types"
+	}
+	return fmt.Sprintf("unable to load one or more of the requested types: %s", strings.Join(e.LoaderExceptions, "; "))
+}
+
+// FileNotFoundError is the FileNotFoundException thrown when an assembly file, or one of the assemblies it depends on,
+// can not be found. It matches fs.ErrNotExist with errors.Is
+// https://docs.microsoft.com/en-us/dotnet/api/system.io.filenotfoundexception?view=netframework-4.8
+type FileNotFoundError struct {
+	// Path is the assembly that was being loaded
+	Path string
+	// HRESULT is the code returned by the CLR
+	HRESULT uint32
+}
+
+func (e *FileNotFoundError) Error() string {
+	return fmt.Sprintf("the %s assembly or one of its dependencies could not be found (HRESULT 0x%x)", e.Path, e.HRESULT)
+}
+
+func (e *FileNotFoundError) Unwrap() error {
+	return fs.ErrNotExist
+}
+
+// BadImageFormatError is the BadImageFormatException thrown when an assembly file, or one of the assemblies it depends
+// on, is not a valid assembly for the loaded runtime: a native image, a corrupted file, an assembly built for a newer
+// runtime or for a processor architecture the process can not load
+// https://docs.microsoft.com/en-us/dotnet/api/system.badimageformatexception?view=netframework-4.8
+type BadImageFormatError struct {
+	// Path is the assembly that was being loaded
+	Path string
+	// HRESULT is the code returned by the CLR
+	HRESULT uint32
+}
+
+func (e *BadImageFormatError) Error() string {
+	return fmt.Sprintf("the %s assembly or one of its dependencies has an invalid format (HRESULT 0x%x)", e.Path, e.HRESULT)
+}
+
+// assemblyLoadError returns the typed error of an HRESULT returned while loading the assembly at path, or nil if the
+// HRESULT is not a FileNotFoundException or BadImageFormatException
+func assemblyLoadError(hr uint32, path string) error {
+	switch hr {
+	case COR_E_FILENOTFOUND, COR_E_DIRECTORYNOTFOUND:
+		return &FileNotFoundError{Path: path, HRESULT: hr}
+	case COR_E_BADIMAGEFORMAT, COR_E_NEWER_RUNTIME, COR_E_ASSEMBLYEXPECTED:
+		return &BadImageFormatError{Path: path, HRESULT: hr}
+	}
+	return nil
+}
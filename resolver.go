@@ -0,0 +1,76 @@
+package clr
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// AssemblyResolver returns the raw bytes of the assembly with the requested name, or false if it can't provide it.
+// It is called through AppDomain.AddAssemblyResolve when the CLR can't find an assembly referenced by a loaded one
+type AssemblyResolver func(name AssemblyName) ([]byte, bool)
+
+// MapResolver resolves assemblies from a map of display names to raw bytes. The keys are usually simple names, such as
+// "Newtonsoft.Json", and a requested name is resolved by the first key, in sorted order, it Matches: a key only
+// restricts the attributes it specifies, so "Newtonsoft.Json, Version=13.0.0.0" only serves that version. Keys that
+// are not valid display names are ignored
+func MapResolver(assemblies map[string][]byte) AssemblyResolver {
+	type entry struct {
+		name AssemblyName
+		raw  []byte
+	}
+	keys := make([]string, 0, len(assemblies))
+	for key := range assemblies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	entries := make([]entry, 0, len(keys))
+	for _, key := range keys {
+		if name, err := ParseAssemblyName(key); err == nil {
+			entries = append(entries, entry{name: name, raw: assemblies[key]})
+		}
+	}
+	return func(name AssemblyName) ([]byte, bool) {
+		for _, e := range entries {
+			if e.name.Matches(name) {
+				return e.raw, true
+			}
+		}
+		return nil, false
+	}
+}
+
+// FSResolver resolves assemblies from the files of fsys named after their simple name with a .dll or .exe extension,
+// as the CLR probes the application base. The satellite assemblies of a culture are looked for in a directory named
+// after it first, such as "fr-FR/Tools.resources.dll"
+func FSResolver(fsys fs.FS) AssemblyResolver {
+	return func(name AssemblyName) ([]byte, bool) {
+		if strings.ContainsAny(name.Name, `/\`) {
+			return nil, false
+		}
+		var dirs []string
+		if name.Culture != "" && !strings.EqualFold(name.Culture, "neutral") {
+			dirs = append(dirs, name.Culture)
+		}
+		dirs = append(dirs, ".")
+		for _, dir := range dirs {
+			for _, ext := range []string{".dll", ".exe"} {
+				file := path.Join(dir, name.Name+ext)
+				if !fs.ValidPath(file) {
+					continue
+				}
+				if raw, err := fs.ReadFile(fsys, file); err == nil {
+					return raw, true
+				}
+			}
+		}
+		return nil, false
+	}
+}
+
+// DirResolver resolves assemblies from the files of the directory dir, as FSResolver does
+func DirResolver(dir string) AssemblyResolver {
+	return FSResolver(os.DirFS(dir))
+}
@@ -0,0 +1,144 @@
+package clr
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+// resolve parses a display name and resolves it, the result is the content of the assembly or "" when it isn't found
+func resolve(t *testing.T, resolver AssemblyResolver, displayName string) string {
+	t.Helper()
+	name, err := ParseAssemblyName(displayName)
+	if err != nil {
+		t.Fatalf("ParseAssemblyName(%q) returned an error: %s", displayName, err)
+	}
+	raw, ok := resolver(name)
+	if ok != (raw != nil) {
+		t.Errorf("the resolver returned %q and %t for %q", raw, ok, displayName)
+	}
+	return string(raw)
+}
+
+func TestMapResolver(t *testing.T) {
+	resolver := MapResolver(map[string][]byte{
+		// Keys are tried in sorted order, "Foo, Version=1.0.0.0" before "foo"
+		"foo":                   []byte("any foo"),
+		"Foo, Version=1.0.0.0":  []byte("foo 1"),
+		"Bar, Version=2.0.0.0":  []byte("bar 2"),
+		"Baz, Culture=fr-FR":    []byte("baz fr"),
+		"Baz":                   []byte("baz"),
+		"Invalid, Version=1.x":  []byte("invalid"),
+		"Invalid, Culture=a, ,": []byte("invalid"),
+	})
+	tests := []struct {
+		displayName string
+		want        string
+	}{
+		{"Foo, Version=1.0.0.0, Culture=neutral, PublicKeyToken=null", "foo 1"},
+		{"Foo, Version=2.0.0.0, Culture=neutral, PublicKeyToken=null", "any foo"},
+		{"FOO", "any foo"},
+		// A versioned key doesn't serve the other versions
+		{"Bar, Version=2.0.0.0, Culture=neutral, PublicKeyToken=null", "bar 2"},
+		{"Bar, Version=2.0.0.1, Culture=neutral, PublicKeyToken=null", ""},
+		{"Bar", ""},
+		// "Baz" sorts before "Baz, Culture=fr-FR" and serves every culture
+		{"Baz, Version=1.0.0.0, Culture=fr-FR, PublicKeyToken=null", "baz"},
+		// Keys that are not valid display names are ignored
+		{"Invalid, Version=1.0.0.0", ""},
+		{"Invalid", ""},
+		{"Missing", ""},
+		{"FooBar", ""},
+	}
+	for _, tt := range tests {
+		if got := resolve(t, resolver, tt.displayName); got != tt.want {
+			t.Errorf("MapResolver resolved %q to %q, want %q", tt.displayName, got, tt.want)
+		}
+	}
+
+	resolver = MapResolver(map[string][]byte{"Baz, Culture=fr-FR": []byte("baz fr"), "Baz, Culture=neutral": []byte("baz")})
+	for displayName, want := range map[string]string{
+		"Baz, Version=1.0.0.0, Culture=fr-FR, PublicKeyToken=null":   "baz fr",
+		"Baz, Version=1.0.0.0, Culture=neutral, PublicKeyToken=null": "baz",
+		"Baz, Version=1.0.0.0, Culture=de-DE, PublicKeyToken=null":   "",
+	} {
+		if got := resolve(t, resolver, displayName); got != want {
+			t.Errorf("MapResolver resolved %q to %q, want %q", displayName, got, want)
+		}
+	}
+}
+
+func TestFSResolver(t *testing.T) {
+	resolver := FSResolver(fstest.MapFS{
+		"Tools.dll":                 {Data: []byte("tools dll")},
+		"Tools.exe":                 {Data: []byte("tools exe")},
+		"Runner.exe":                {Data: []byte("runner exe")},
+		"Tools.resources.dll":       {Data: []byte("neutral resources")},
+		"fr-FR/Tools.resources.dll": {Data: []byte("fr-FR resources")},
+		"de-DE/Tools.resources.exe": {Data: []byte("de-DE resources")},
+		"sub/Hidden.dll":            {Data: []byte("hidden")},
+		"Upper.DLL":                 {Data: []byte("upper")},
+		"Directory.dll/Nested.dll":  {Data: []byte("nested")},
+		"neutral/Neutral.dll":       {Data: []byte("neutral directory")},
+		"Neutral.dll":               {Data: []byte("neutral")},
+		"fr-FR/Satellite.only.dll":  {Data: []byte("satellite")},
+	})
+	tests := []struct {
+		displayName string
+		want        string
+	}{
+		// .dll is probed before .exe
+		{"Tools", "tools dll"},
+		{"Tools, Version=1.0.0.0, Culture=neutral, PublicKeyToken=null", "tools dll"},
+		{"Runner", "runner exe"},
+		// The culture directory is probed before the application base, from .dll to .exe
+		{"Tools.resources, Culture=fr-FR", "fr-FR resources"},
+		{"Tools.resources, Culture=de-DE", "de-DE resources"},
+		{"Tools.resources, Culture=it-IT", "neutral resources"},
+		{"Tools.resources", "neutral resources"},
+		{"Neutral, Culture=neutral", "neutral"},
+		{"Satellite.only, Culture=fr-FR", "satellite"},
+		{"Satellite.only", ""},
+		// Only the files named after the simple name are served
+		{"Hidden", ""},
+		{"Upper", ""},
+		{"Directory", ""},
+		{"Missing", ""},
+	}
+	for _, tt := range tests {
+		if got := resolve(t, resolver, tt.displayName); got != tt.want {
+			t.Errorf("FSResolver resolved %q to %q, want %q", tt.displayName, got, tt.want)
+		}
+	}
+
+	// Names with path separators never leave the directory they are looked for in
+	for _, name := range []AssemblyName{
+		{Name: "sub/Hidden"},
+		{Name: `sub\Hidden`},
+		{Name: "../Tools"},
+		{Name: `..\Tools`},
+	} {
+		if raw, ok := resolver(name); ok {
+			t.Errorf("FSResolver resolved %#v to %q", name, raw)
+		}
+	}
+	// A culture that is not a valid directory name is only probed in the application base
+	if raw, _ := resolver(AssemblyName{Name: "Tools.resources", Culture: "../fr-FR"}); string(raw) != "neutral resources" {
+		t.Errorf("FSResolver resolved the ../fr-FR culture to %q, want the neutral resources", raw)
+	}
+}
+
+func TestDirResolver(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Tools.dll"), []byte("tools"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	resolver := DirResolver(dir)
+	if got := resolve(t, resolver, "Tools"); got != "tools" {
+		t.Errorf("DirResolver resolved Tools to %q, want tools", got)
+	}
+	if got := resolve(t, resolver, "Missing"); got != "" {
+		t.Errorf("DirResolver resolved Missing to %q", got)
+	}
+}
@@ -0,0 +1,152 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"syscall"
+	"unsafe"
+)
+
+// resourceChunkSize is the number of bytes copied out of a managed stream by each call to BinaryReader.ReadBytes
+const resourceChunkSize = 64 * 1024
+
+// ResourceNames returns the names of the resources embedded in the assembly
+//
+//	virtual HRESULT __stdcall GetManifestResourceNames (
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.getmanifestresourcenames?view=netframework-4.8
+func (obj *Assembly) ResourceNames() ([]string, error) {
+	debugPrint("Entering into assembly.ResourceNames()...")
+	var psa *SafeArray
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetManifestResourceNames,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&psa)),
+	)
+	if hr != S_OK {
+		return nil, fmt.Errorf("the Assembly::GetManifestResourceNames method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	names, err := WrapSafeArray[string](psa)
+	if err != nil {
+		SafeArrayDestroy(psa)
+		return nil, err
+	}
+	defer names.Destroy()
+	return names.ToSlice()
+}
+
+// OpenResource opens the resource embedded in the assembly with the specified case-sensitive name, as listed by
+// ResourceNames. The managed stream is read in chunks copied through a byte[] SAFEARRAY, so resources can be read from
+// any loaded assembly, including dynamic ones. The returned reader must be closed to release the stream. An error
+// wrapping fs.ErrNotExist is returned if the assembly has no such resource
+//
+//	virtual HRESULT __stdcall GetManifestResourceStream_2 (
+//	/*[in]*/ BSTR name,
+//	/*[out,retval]*/ struct _Stream * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.assembly.getmanifestresourcestream?view=netframework-4.8#System_Reflection_Assembly_GetManifestResourceStream_System_String_
+func (obj *Assembly) OpenResource(name string) (io.ReadCloser, error) {
+	debugPrint("Entering into assembly.OpenResource()...")
+	bstrName, err := SysAllocString(name)
+	if err != nil {
+		return nil, err
+	}
+	defer bstrName.Free()
+
+	var stream *IUnknown
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetManifestResourceStream_2,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrName.Pointer()),
+		uintptr(unsafe.Pointer(&stream)),
+	)
+	if hr != S_OK {
+		return nil, fmt.Errorf("the Assembly::GetManifestResourceStream_2 method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	if stream == nil {
+		return nil, fmt.Errorf("the %s resource was not found in the assembly: %w", name, fs.ErrNotExist)
+	}
+	reader, err := newStreamReader(stream)
+	if err != nil {
+		stream.Release()
+		return nil, err
+	}
+	return reader, nil
+}
+
+// streamReader reads a managed System.IO.Stream. Stream.Read fills a buffer passed as an argument, which isn't copied
+// back through COM, so the stream is read with a BinaryReader whose ReadBytes method returns a new byte[]
+// https://docs.microsoft.com/en-us/dotnet/api/system.io.binaryreader.readbytes?view=netframework-4.8
+type streamReader struct {
+	stream     *IUnknown
+	reader     *IUnknown
+	readerType *Type
+	// chunk holds the bytes returned by ReadBytes that were not read yet
+	chunk []byte
+}
+
+// newStreamReader creates a BinaryReader over the stream. The streamReader takes over the reference held on stream
+func newStreamReader(stream *IUnknown) (*streamReader, error) {
+	streamType, err := stream.GetType()
+	if err != nil {
+		return nil, err
+	}
+	defer streamType.Release()
+
+	readerType, err := mscorlibType(streamType, "System.IO.BinaryReader")
+	if err != nil {
+		return nil, err
+	}
+	reader, err := readerType.InvokeMember("", BindingFlags_CreateInstance|BindingFlags_Public|BindingFlags_Instance, nil, stream)
+	if err != nil {
+		readerType.Release()
+		return nil, fmt.Errorf("there was an error creating a BinaryReader:\n%s", err)
+	}
+	unknown, ok := reader.(*IUnknown)
+	if !ok || unknown == nil {
+		readerType.Release()
+		return nil, fmt.Errorf("the BinaryReader constructor returned a %T", reader)
+	}
+	return &streamReader{stream: stream, reader: unknown, readerType: readerType}, nil
+}
+
+func (r *streamReader) Read(p []byte) (int, error) {
+	if r.reader == nil {
+		return 0, fs.ErrClosed
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if len(r.chunk) == 0 {
+		chunk, err := r.readerType.InvokeMember("ReadBytes", BindingFlags_InvokeMethod|BindingFlags_Public|BindingFlags_Instance, r.reader, int32(resourceChunkSize))
+		if err != nil {
+			return 0, fmt.Errorf("there was an error reading the stream:\n%s", err)
+		}
+		// ReadBytes returns an empty array at the end of the stream
+		r.chunk, _ = chunk.([]byte)
+		if len(r.chunk) == 0 {
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+// Close closes the BinaryReader, which closes the stream, and releases them
+func (r *streamReader) Close() error {
+	if r.reader == nil {
+		return fs.ErrClosed
+	}
+	_, err := r.readerType.InvokeMember("Close", BindingFlags_InvokeMethod|BindingFlags_Public|BindingFlags_Instance, r.reader)
+	r.readerType.Release()
+	r.reader.Release()
+	r.stream.Release()
+	r.readerType, r.reader, r.stream, r.chunk = nil, nil, nil, nil
+	return err
+}
@@ -0,0 +1,394 @@
+package clr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// NewSafeArrayBound returns the bounds of one SAFEARRAY dimension holding count elements with the first at index lower
+func NewSafeArrayBound(lower int32, count uint32) SafeArrayBound {
+	return SafeArrayBound{cElements: count, lLbound: lower}
+}
+
+// LowerBound returns the index of the first element of the dimension
+func (b SafeArrayBound) LowerBound() int32 {
+	return b.lLbound
+}
+
+// Count returns the number of elements in the dimension
+func (b SafeArrayBound) Count() uint32 {
+	return b.cElements
+}
+
+// SafeArrayOf is a SAFEARRAY of any number of dimensions whose elements are the Go type T. T is one of the types
+// ToVariant maps to a Variant Type: the sized integer and float types, bool, string, Currency, Date, SCode, Decimal,
+// *IUnknown, or any for an array of VT_VARIANT.
+// Strings, VARIANTs and interface pointers are copied in and out with SafeArrayPutElement and SafeArrayGetElement,
+// the other types are plain memory and can be reached in place with Access
+type SafeArrayOf[T any] struct {
+	psa *SafeArray
+	vt  uint16
+}
+
+// safeArrayVartype returns the Variant Type of the elements of a SafeArrayOf[T]
+func safeArrayVartype[T any]() (uint16, error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	vt, ok := variantTypes[t]
+	if !ok || vt == VT_DISPATCH {
+		return 0, fmt.Errorf("the %s type can not be stored in a SAFEARRAY", t)
+	}
+	return vt, nil
+}
+
+// NewSafeArrayOf creates a SAFEARRAY with one dimension for each bound, given from the leftmost dimension to the
+// rightmost. The caller owns the array and must free it with Destroy
+func NewSafeArrayOf[T any](bounds ...SafeArrayBound) (*SafeArrayOf[T], error) {
+	debugPrint("Entering into safearrayof.NewSafeArrayOf()...")
+	vt, err := safeArrayVartype[T]()
+	if err != nil {
+		return nil, err
+	}
+	if len(bounds) == 0 {
+		return nil, errors.New("a SAFEARRAY must have at least one dimension")
+	}
+	psa, err := SafeArrayCreate(vt, uint32(len(bounds)), &bounds[0])
+	if err != nil {
+		return nil, err
+	}
+	return &SafeArrayOf[T]{psa: psa, vt: vt}, nil
+}
+
+// SafeArrayFromSlice creates a one dimensional SAFEARRAY indexed from zero holding a copy of s.
+// The caller owns the array and must free it with Destroy
+func SafeArrayFromSlice[T any](s []T) (*SafeArrayOf[T], error) {
+	a := &SafeArrayOf[T]{}
+	if err := a.FromSlice(s); err != nil {
+		a.Destroy()
+		return nil, err
+	}
+	return a, nil
+}
+
+// WrapSafeArray returns a SafeArrayOf[T] for an existing SAFEARRAY, such as one returned by managed code, after
+// checking its elements are of type T. An array of VT_DISPATCH can be wrapped as a SafeArrayOf[*IUnknown].
+// The SAFEARRAY is not copied, Destroy frees it
+func WrapSafeArray[T any](psa *SafeArray) (*SafeArrayOf[T], error) {
+	debugPrint("Entering into safearrayof.WrapSafeArray()...")
+	if psa == nil {
+		return nil, errors.New("the SAFEARRAY pointer is nil")
+	}
+	vt, err := safeArrayVartype[T]()
+	if err != nil {
+		return nil, err
+	}
+	actual, err := SafeArrayGetVartype(psa)
+	if err != nil {
+		return nil, err
+	}
+	if actual != vt && !(vt == VT_UNKNOWN && actual == VT_DISPATCH) {
+		return nil, fmt.Errorf("the SAFEARRAY holds elements of VARIANT type 0x%x, not 0x%x", actual, vt)
+	}
+	return &SafeArrayOf[T]{psa: psa, vt: actual}, nil
+}
+
+// interfacesFromSafeArray reads a SAFEARRAY of interface pointers returned by a COM method, such as the
+// SAFEARRAY(_Assembly*) of AppDomain.GetAssemblies, and destroys it. Each element is returned as a pointer to the
+// wrapper type T and holds a new reference that belongs to the caller
+func interfacesFromSafeArray[T any](psa *SafeArray) ([]*T, error) {
+	array, err := WrapSafeArray[*IUnknown](psa)
+	if err != nil {
+		SafeArrayDestroy(psa)
+		return nil, err
+	}
+	defer array.Destroy()
+
+	elements, err := array.ToSlice()
+	if err != nil {
+		return nil, err
+	}
+	interfaces := make([]*T, 0, len(elements))
+	for _, element := range elements {
+		interfaces = append(interfaces, (*T)(unsafe.Pointer(element)))
+	}
+	return interfaces, nil
+}
+
+// CreateSafeArray is a wrapper function that takes in a Go byte array and creates a SafeArray containing unsigned bytes
+func CreateSafeArray(rawBytes []byte) (*SafeArray, error) {
+	debugPrint("Entering into safearrayof.CreateSafeArray()...")
+	a, err := SafeArrayFromSlice(rawBytes)
+	if err != nil {
+		return nil, err
+	}
+	return a.SafeArray(), nil
+}
+
+// SafeArray returns the underlying SAFEARRAY descriptor
+func (a *SafeArrayOf[T]) SafeArray() *SafeArray {
+	return a.psa
+}
+
+// Vartype returns the Variant Type of the array elements
+func (a *SafeArrayOf[T]) Vartype() uint16 {
+	return a.vt
+}
+
+// Variant returns a VT_ARRAY VARIANT holding the array. The VARIANT takes ownership of the array, clearing it destroys
+// the array, and the SafeArrayOf can no longer be used
+func (a *SafeArrayOf[T]) Variant() Variant {
+	v := Variant{VT: a.vt | VT_ARRAY}
+	v.setPtr(unsafe.Pointer(a.psa))
+	a.psa = nil
+	return v
+}
+
+// Dims returns the number of dimensions of the array
+func (a *SafeArrayOf[T]) Dims() (uint32, error) {
+	if a.psa == nil {
+		return 0, errors.New("the SafeArrayOf does not hold a SAFEARRAY")
+	}
+	return SafeArrayGetDim(a.psa)
+}
+
+// Bounds returns the bounds of every dimension from the leftmost to the rightmost
+func (a *SafeArrayOf[T]) Bounds() ([]SafeArrayBound, error) {
+	dims, err := a.Dims()
+	if err != nil {
+		return nil, err
+	}
+	bounds := make([]SafeArrayBound, dims)
+	for d := uint32(1); d <= dims; d++ {
+		lower, err := SafeArrayGetLBound(a.psa, d)
+		if err != nil {
+			return nil, err
+		}
+		upper, err := SafeArrayGetUBound(a.psa, d)
+		if err != nil {
+			return nil, err
+		}
+		bounds[d-1] = NewSafeArrayBound(lower, uint32(int64(upper)-int64(lower)+1))
+	}
+	return bounds, nil
+}
+
+// Len returns the total number of elements in every dimension of the array
+func (a *SafeArrayOf[T]) Len() (int, error) {
+	bounds, err := a.Bounds()
+	if err != nil {
+		return 0, err
+	}
+	n := 1
+	for _, b := range bounds {
+		n *= int(b.cElements)
+	}
+	return n, nil
+}
+
+// checkIndices makes sure there is one index for each dimension so oleaut32 does not read past the indices
+func (a *SafeArrayOf[T]) checkIndices(indices []int32) error {
+	dims, err := a.Dims()
+	if err != nil {
+		return err
+	}
+	if uint32(len(indices)) != dims {
+		return fmt.Errorf("the SAFEARRAY has %d dimensions but %d indices were provided", dims, len(indices))
+	}
+	return nil
+}
+
+// Put stores value at the element with the given indices, one for each dimension from the leftmost to the rightmost.
+// Strings, VARIANTs and interface pointers are copied, the caller keeps its own reference
+func (a *SafeArrayOf[T]) Put(value T, indices ...int32) error {
+	if err := a.checkIndices(indices); err != nil {
+		return err
+	}
+	switch a.vt {
+	case VT_VARIANT:
+		if v, ok := any(value).(Variant); ok {
+			return SafeArrayPutElement(a.psa, indices, unsafe.Pointer(&v))
+		}
+		v, err := ToVariant(any(value))
+		if err != nil {
+			return err
+		}
+		defer v.Clear()
+		return SafeArrayPutElement(a.psa, indices, unsafe.Pointer(&v))
+	case VT_BOOL:
+		b := variantBool(any(value).(bool))
+		return SafeArrayPutElement(a.psa, indices, unsafe.Pointer(&b))
+	case VT_BSTR:
+		bstr, err := SysAllocString(any(value).(string))
+		if err != nil {
+			return err
+		}
+		defer bstr.Free()
+		return SafeArrayPutElement(a.psa, indices, bstr.Pointer())
+	case VT_UNKNOWN, VT_DISPATCH:
+		return SafeArrayPutElement(a.psa, indices, unsafe.Pointer(any(value).(*IUnknown)))
+	}
+	return SafeArrayPutElement(a.psa, indices, unsafe.Pointer(&value))
+}
+
+// Get returns the element with the given indices, one for each dimension from the leftmost to the rightmost.
+// A returned *IUnknown holds a new reference that belongs to the caller
+func (a *SafeArrayOf[T]) Get(indices ...int32) (value T, err error) {
+	if err = a.checkIndices(indices); err != nil {
+		return
+	}
+	switch a.vt {
+	case VT_VARIANT:
+		var v Variant
+		if err = SafeArrayGetElement(a.psa, indices, unsafe.Pointer(&v)); err != nil {
+			return
+		}
+		// The reference held by the copied VARIANT is handed to the caller with the interface pointer
+		var x any
+		x, err = takeVariant(&v)
+		value, _ = x.(T)
+		return
+	case VT_BOOL:
+		var b int16
+		err = SafeArrayGetElement(a.psa, indices, unsafe.Pointer(&b))
+		value, _ = any(b != 0).(T)
+		return
+	case VT_BSTR:
+		var bstr BSTR
+		if err = SafeArrayGetElement(a.psa, indices, unsafe.Pointer(&bstr)); err != nil {
+			return
+		}
+		value, _ = any(bstr.String()).(T)
+		bstr.Free()
+		return
+	case VT_UNKNOWN, VT_DISPATCH:
+		var p *IUnknown
+		err = SafeArrayGetElement(a.psa, indices, unsafe.Pointer(&p))
+		value, _ = any(p).(T)
+		return
+	}
+	err = SafeArrayGetElement(a.psa, indices, unsafe.Pointer(&value))
+	return
+}
+
+// plain reports whether the elements are stored in the same memory layout as T
+func (a *SafeArrayOf[T]) plain() bool {
+	switch a.vt {
+	case VT_VARIANT, VT_BOOL, VT_BSTR, VT_UNKNOWN, VT_DISPATCH:
+		return false
+	}
+	return true
+}
+
+// Access locks the array and calls fn with its elements in place, in the order they are stored: the leftmost index
+// changes fastest. The slice must not be used after fn returns. Only arrays of numbers, Currency, Date, SCode and
+// Decimal can be accessed, the other element types are not stored as T
+func (a *SafeArrayOf[T]) Access(fn func(data []T) error) (err error) {
+	if !a.plain() {
+		return fmt.Errorf("SAFEARRAYs of VARIANT type 0x%x can not be accessed in place", a.vt)
+	}
+	n, err := a.Len()
+	if err != nil {
+		return err
+	}
+	data, err := SafeArrayAccessData(a.psa)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := SafeArrayUnaccessData(a.psa); err == nil {
+			err = e
+		}
+	}()
+	if n == 0 {
+		return fn(nil)
+	}
+	return fn(unsafe.Slice((*T)(data), n))
+}
+
+// each calls fn with the indices of every element in the order they are stored, the leftmost index changes fastest
+func (a *SafeArrayOf[T]) each(fn func(i int, indices []int32) error) error {
+	bounds, err := a.Bounds()
+	if err != nil {
+		return err
+	}
+	n := 1
+	indices := make([]int32, len(bounds))
+	for d, b := range bounds {
+		n *= int(b.cElements)
+		indices[d] = b.lLbound
+	}
+	for i := 0; i < n; i++ {
+		if err = fn(i, indices); err != nil {
+			return fmt.Errorf("element %v: %w", indices, err)
+		}
+		for d := range indices {
+			indices[d]++
+			if indices[d] < bounds[d].lLbound+int32(bounds[d].cElements) {
+				break
+			}
+			indices[d] = bounds[d].lLbound
+		}
+	}
+	return nil
+}
+
+// ToSlice copies every element of the array into a slice, in the order they are stored: the leftmost index changes
+// fastest
+func (a *SafeArrayOf[T]) ToSlice() ([]T, error) {
+	n, err := a.Len()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]T, n)
+	if a.plain() {
+		return out, a.Access(func(data []T) error {
+			copy(out, data)
+			return nil
+		})
+	}
+	return out, a.each(func(i int, indices []int32) (err error) {
+		out[i], err = a.Get(indices...)
+		return
+	})
+}
+
+// FromSlice copies s into the array in the order the elements are stored, the leftmost index changes fastest.
+// When the SafeArrayOf does not hold a SAFEARRAY yet, a one dimensional array indexed from zero is created,
+// otherwise s must have exactly one value for each element
+func (a *SafeArrayOf[T]) FromSlice(s []T) error {
+	debugPrint("Entering into safearrayof.FromSlice()...")
+	if a.psa == nil {
+		created, err := NewSafeArrayOf[T](NewSafeArrayBound(0, uint32(len(s))))
+		if err != nil {
+			return err
+		}
+		*a = *created
+	}
+	n, err := a.Len()
+	if err != nil {
+		return err
+	}
+	if n != len(s) {
+		return fmt.Errorf("the SAFEARRAY has %d elements but the slice has %d", n, len(s))
+	}
+	if a.plain() {
+		return a.Access(func(data []T) error {
+			copy(data, s)
+			return nil
+		})
+	}
+	return a.each(func(i int, indices []int32) error {
+		return a.Put(s[i], indices...)
+	})
+}
+
+// Destroy frees the array and every string, VARIANT and interface reference it holds
+func (a *SafeArrayOf[T]) Destroy() error {
+	if a.psa == nil {
+		return nil
+	}
+	err := SafeArrayDestroy(a.psa)
+	a.psa = nil
+	return err
+}
@@ -0,0 +1,300 @@
+package clr
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Parameter describes a parameter of a managed method, read from its ParameterInfo
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.parameterinfo?view=net-5.0
+type Parameter struct {
+	// Name is the name of the parameter, empty if it has none
+	Name string
+	// Position is the zero-based position of the parameter
+	Position int
+	// Type is the name of the parameter's type as returned by Type.ToString, such as "System.String[]". The type of a
+	// ref or out parameter ends with "&"
+	Type string
+	// IsOut is true for out parameters
+	IsOut bool
+	// IsOptional is true for parameters that can be omitted
+	IsOptional bool
+	// HasDefault is true if the parameter has a default value, Default holds it
+	HasDefault bool
+	Default    any
+	// IsParamArray is true for a params array, the last parameter of the method, which takes any number of arguments
+	IsParamArray bool
+}
+
+// Signature describes a managed method: its name, return type and parameters. It is read once from the MethodInfo
+// with MethodInfo.Signature so the method can be inspected, and its arguments checked and converted with Bind, before
+// it is invoked
+type Signature struct {
+	// Name is the name of the method
+	Name string
+	// DeclaringType is the full name of the type that declares the method, empty for global methods
+	DeclaringType string
+	// ReturnType is the name of the returned type, "System.Void" for methods that return nothing
+	ReturnType string
+	// IsStatic is true for static methods
+	IsStatic bool
+	// Attributes are the flags of the method
+	Attributes MethodAttributes
+	// Parameters are the parameters of the method in order
+	Parameters []Parameter
+}
+
+// managedGoTypes maps the name of a managed primitive type to the Go type ToVariant converts to it
+var managedGoTypes = map[string]reflect.Type{
+	"System.Boolean": reflect.TypeOf(false),
+	"System.SByte":   reflect.TypeOf(int8(0)),
+	"System.Int16":   reflect.TypeOf(int16(0)),
+	"System.Int32":   reflect.TypeOf(int32(0)),
+	"System.Int64":   reflect.TypeOf(int64(0)),
+	"System.Byte":    reflect.TypeOf(uint8(0)),
+	"System.UInt16":  reflect.TypeOf(uint16(0)),
+	"System.UInt32":  reflect.TypeOf(uint32(0)),
+	"System.UInt64":  reflect.TypeOf(uint64(0)),
+	"System.Single":  reflect.TypeOf(float32(0)),
+	"System.Double":  reflect.TypeOf(float64(0)),
+	"System.String":  reflect.TypeOf(""),
+}
+
+// String returns the signature in a C# like syntax, such as
+// "static System.Int32 Tools.Math.Sum(System.Int32 first, System.Int32 second = 0, params System.Int32[] rest)"
+func (s *Signature) String() string {
+	var b strings.Builder
+	if s.IsStatic {
+		b.WriteString("static ")
+	}
+	b.WriteString(s.ReturnType)
+	b.WriteString(" ")
+	if s.DeclaringType != "" {
+		b.WriteString(s.DeclaringType)
+		b.WriteString(".")
+	}
+	b.WriteString(s.Name)
+	b.WriteString("(")
+	for i, p := range s.Parameters {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		typeName, byRef := strings.CutSuffix(p.Type, "&")
+		switch {
+		case p.IsParamArray:
+			b.WriteString("params ")
+		case byRef && p.IsOut:
+			b.WriteString("out ")
+		case byRef:
+			b.WriteString("ref ")
+		case p.IsOptional && !p.HasDefault:
+			b.WriteString("[optional] ")
+		}
+		b.WriteString(typeName)
+		if p.Name != "" {
+			b.WriteString(" ")
+			b.WriteString(p.Name)
+		}
+		if p.HasDefault {
+			fmt.Fprintf(&b, " = %s", formatDefault(p.Default))
+		}
+	}
+	b.WriteString(")")
+	return b.String()
+}
+
+// MinArgs returns the number of arguments that must be provided, the parameters before the first optional parameter
+// or params array
+func (s *Signature) MinArgs() int {
+	for i, p := range s.Parameters {
+		if p.IsOptional || p.HasDefault || p.IsParamArray {
+			return i
+		}
+	}
+	return len(s.Parameters)
+}
+
+// MaxArgs returns the number of arguments that can be provided, or -1 if the method takes a params array
+func (s *Signature) MaxArgs() int {
+	if n := len(s.Parameters); n > 0 && s.Parameters[n-1].IsParamArray {
+		return -1
+	}
+	return len(s.Parameters)
+}
+
+// Bind checks the number of arguments against the parameters and returns one argument for each parameter, ready to be
+// passed to MethodInfo.Invoke_3:
+//
+//   - numbers are converted to the Go type of a primitive parameter, such as int to int64 for a System.Int64, as long
+//     as the value fits; the elements of slices passed for arrays of primitives are converted the same way
+//   - omitted parameters take their default value, or Type.Missing if they are optional without one
+//   - the arguments passed for a params array are collected into an array of its element type, a single argument that
+//     already is an array of the element type is passed as is
+//
+// Other arguments are left to ToVariant and the CLR
+func (s *Signature) Bind(args []any) ([]any, error) {
+	if minArgs, maxArgs := s.MinArgs(), s.MaxArgs(); len(args) < minArgs || (maxArgs >= 0 && len(args) > maxArgs) {
+		expected := fmt.Sprint(minArgs)
+		switch {
+		case maxArgs < 0:
+			expected = fmt.Sprintf("at least %d", minArgs)
+		case maxArgs != minArgs:
+			expected = fmt.Sprintf("%d to %d", minArgs, maxArgs)
+		}
+		return nil, fmt.Errorf("the %s method takes %s arguments but %d were provided", s, expected, len(args))
+	}
+
+	bound := make([]any, len(s.Parameters))
+	for i, p := range s.Parameters {
+		var err error
+		switch {
+		case p.IsParamArray:
+			bound[i], err = bindParamArray(args[min(i, len(args)):], p.Type)
+		case i < len(args):
+			bound[i], err = convertArg(args[i], p.Type)
+		case p.HasDefault:
+			bound[i] = p.Default
+		default:
+			// DISP_E_PARAMNOTFOUND is marshaled as System.Reflection.Missing
+			bound[i] = SCode(DISP_E_PARAMNOTFOUND)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("the %s parameter of %s: %w", p.Name, s.Name, err)
+		}
+	}
+	return bound, nil
+}
+
+// bindParamArray collects the arguments passed for a params array into an array of its element type
+func bindParamArray(args []any, arrayType string) (any, error) {
+	if len(args) == 1 && args[0] != nil {
+		// A []byte is a single element unless the array is a byte[]
+		rv := reflect.ValueOf(args[0])
+		if rv.Kind() == reflect.Slice && (rv.Type() != reflect.TypeOf([]byte(nil)) || arrayType == "System.Byte[]") {
+			return convertArg(args[0], arrayType)
+		}
+	}
+	elementType := strings.TrimSuffix(arrayType, "[]")
+	goType, ok := managedGoTypes[elementType]
+	if !ok {
+		goType = reflect.TypeOf((*any)(nil)).Elem()
+	}
+	array := reflect.MakeSlice(reflect.SliceOf(goType), len(args), len(args))
+	for i, arg := range args {
+		converted, err := convertArg(arg, elementType)
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		if converted == nil {
+			continue
+		}
+		value := reflect.ValueOf(converted)
+		if !value.Type().AssignableTo(goType) {
+			return nil, fmt.Errorf("element %d: a %T can not be passed as a %s", i, arg, elementType)
+		}
+		array.Index(i).Set(value)
+	}
+	return array.Interface(), nil
+}
+
+// convertArg converts a number to the Go type of the primitive managed type typeName, or the elements of a slice of
+// numbers for an array of primitives. The value must fit: integers are not truncated and floats must be whole numbers
+// to become integers. Other values are returned as is
+func convertArg(arg any, typeName string) (any, error) {
+	typeName = strings.TrimSuffix(typeName, "&")
+	if arg == nil {
+		return nil, nil
+	}
+	rv := reflect.ValueOf(arg)
+	if elementType, isArray := strings.CutSuffix(typeName, "[]"); isArray {
+		goType, ok := managedGoTypes[elementType]
+		if !ok || rv.Kind() != reflect.Slice || rv.Type().Elem() == goType {
+			return arg, nil
+		}
+		array := reflect.MakeSlice(reflect.SliceOf(goType), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			element := rv.Index(i)
+			if element.Kind() == reflect.Interface {
+				element = element.Elem()
+			}
+			if element.IsValid() && element.Type() == goType {
+				array.Index(i).Set(element)
+				continue
+			}
+			converted, err := convertNumber(element, goType)
+			if err != nil {
+				return nil, fmt.Errorf("element %d: %w", i, err)
+			}
+			if !converted.IsValid() {
+				return arg, nil
+			}
+			array.Index(i).Set(converted)
+		}
+		return array.Interface(), nil
+	}
+	goType, ok := managedGoTypes[typeName]
+	if !ok || rv.Type() == goType {
+		return arg, nil
+	}
+	converted, err := convertNumber(rv, goType)
+	if err != nil || !converted.IsValid() {
+		return arg, err
+	}
+	return converted.Interface(), nil
+}
+
+// convertNumber converts a number to the numeric type goType without losing its value. The returned value is invalid
+// if either of the types is not numeric
+func convertNumber(rv reflect.Value, goType reflect.Type) (reflect.Value, error) {
+	if rv.Kind() == reflect.Interface {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || !isNumberKind(rv.Kind()) || !isNumberKind(goType.Kind()) {
+		return reflect.Value{}, nil
+	}
+	converted := rv.Convert(goType)
+	fits := true
+	switch {
+	case isFloatKind(goType.Kind()):
+		// A float loses precision but not its magnitude
+		fits = !math.IsInf(converted.Float(), 0) || math.IsInf(toFloat(rv), 0)
+	case isFloatKind(rv.Kind()):
+		f := rv.Float()
+		fits = f == math.Trunc(f) && toFloat(converted) == f
+	default:
+		fits = converted.Convert(rv.Type()).Equal(rv) && isNegative(rv) == isNegative(converted)
+	}
+	if !fits {
+		return reflect.Value{}, fmt.Errorf("%v does not fit in a %s", rv.Interface(), goType)
+	}
+	return converted, nil
+}
+
+// formatDefault formats the default value of a parameter the way it is written in C#
+func formatDefault(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return fmt.Sprintf("%q", value)
+	}
+	return fmt.Sprint(value)
+}
+
+// isNegative reports whether an integer is negative
+func isNegative(rv reflect.Value) bool {
+	return isSignedKind(rv.Kind()) && rv.Int() < 0
+}
+
+// toFloat returns a numeric value as a float64
+func toFloat(rv reflect.Value) float64 {
+	switch {
+	case isFloatKind(rv.Kind()):
+		return rv.Float()
+	case isSignedKind(rv.Kind()):
+		return float64(rv.Int())
+	}
+	return float64(rv.Uint())
+}
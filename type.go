@@ -0,0 +1,592 @@
+//go:build windows
+// +build windows
+
+package clr
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// from mscorlib.tlh
+
+// Type is the _Type COM interface of a System.Type, the entry point to reflection over a managed type
+type Type struct {
+	vtbl *TypeVtbl
+}
+
+// TypeVtbl Represents type declarations: class types, interface types, array types, value types, enumeration types,
+// type parameters, generic type definitions, and open or closed constructed generic types.
+// Inheritance: Object -> MemberInfo -> Type
+// Type Class: https://docs.microsoft.com/en-us/dotnet/api/system.type?view=net-5.0
+type TypeVtbl struct {
+	QueryInterface            uintptr
+	AddRef                    uintptr
+	Release                   uintptr
+	GetTypeInfoCount          uintptr
+	GetTypeInfo               uintptr
+	GetIDsOfNames             uintptr
+	Invoke                    uintptr
+	get_ToString              uintptr
+	Equals                    uintptr
+	GetHashCode               uintptr
+	GetType                   uintptr
+	get_MemberType            uintptr
+	get_name                  uintptr
+	get_DeclaringType         uintptr
+	get_ReflectedType         uintptr
+	GetCustomAttributes       uintptr
+	GetCustomAttributes_2     uintptr
+	IsDefined                 uintptr
+	get_Guid                  uintptr
+	get_Module                uintptr
+	get_Assembly              uintptr
+	get_TypeHandle            uintptr
+	get_FullName              uintptr
+	get_Namespace             uintptr
+	get_AssemblyQualifiedName uintptr
+	GetArrayRank              uintptr
+	get_BaseType              uintptr
+	GetConstructors           uintptr
+	GetInterface              uintptr
+	GetInterfaces             uintptr
+	FindInterfaces            uintptr
+	GetEvent                  uintptr
+	GetEvents                 uintptr
+	GetEvents_2               uintptr
+	GetNestedTypes            uintptr
+	GetNestedType             uintptr
+	GetMember                 uintptr
+	GetDefaultMembers         uintptr
+	FindMembers               uintptr
+	GetElementType            uintptr
+	IsSubclassOf              uintptr
+	IsInstanceOfType          uintptr
+	IsAssignableFrom          uintptr
+	GetInterfaceMap           uintptr
+	GetMethod                 uintptr
+	GetMethod_2               uintptr
+	GetMethods                uintptr
+	GetField                  uintptr
+	GetFields                 uintptr
+	GetProperty               uintptr
+	GetProperty_2             uintptr
+	GetProperties             uintptr
+	GetMember_2               uintptr
+	GetMembers                uintptr
+	InvokeMember              uintptr
+	get_UnderlyingSystemType  uintptr
+	InvokeMember_2            uintptr
+	InvokeMember_3            uintptr
+	GetConstructor            uintptr
+	GetConstructor_2          uintptr
+	GetConstructor_3          uintptr
+	GetConstructors_2         uintptr
+	get_TypeInitializer       uintptr
+	GetMethod_3               uintptr
+	GetMethod_4               uintptr
+	GetMethod_5               uintptr
+	GetMethod_6               uintptr
+	GetMethods_2              uintptr
+	GetField_2                uintptr
+	GetFields_2               uintptr
+	GetInterface_2            uintptr
+	GetEvent_2                uintptr
+	GetProperty_3             uintptr
+	GetProperty_4             uintptr
+	GetProperty_5             uintptr
+	GetProperty_6             uintptr
+	GetProperty_7             uintptr
+	GetProperties_2           uintptr
+	GetNestedTypes_2          uintptr
+	GetNestedType_2           uintptr
+	GetMember_3               uintptr
+	GetMembers_2              uintptr
+	get_Attributes            uintptr
+	get_IsNotPublic           uintptr
+	get_IsPublic              uintptr
+	get_IsNestedPublic        uintptr
+	get_IsNestedPrivate       uintptr
+	get_IsNestedFamily        uintptr
+	get_IsNestedAssembly      uintptr
+	get_IsNestedFamANDAssem   uintptr
+	get_IsNestedFamORAssem    uintptr
+	get_IsAutoLayout          uintptr
+	get_IsLayoutSequential    uintptr
+	get_IsExplicitLayout      uintptr
+	get_IsClass               uintptr
+	get_IsInterface           uintptr
+	get_IsValueType           uintptr
+	get_IsAbstract            uintptr
+	get_IsSealed              uintptr
+	get_IsEnum                uintptr
+	get_IsSpecialName         uintptr
+	get_IsImport              uintptr
+	get_IsSerializable        uintptr
+	get_IsAnsiClass           uintptr
+	get_IsUnicodeClass        uintptr
+	get_IsAutoClass           uintptr
+	get_IsArray               uintptr
+	get_IsByRef               uintptr
+	get_IsPointer             uintptr
+	get_IsPrimitive           uintptr
+	get_IsCOMObject           uintptr
+	get_HasElementType        uintptr
+	get_IsContextful          uintptr
+	get_IsMarshalByRef        uintptr
+	Equals_2                  uintptr
+}
+
+func (obj *Type) QueryInterface(riid windows.GUID, ppvObject unsafe.Pointer) error {
+	debugPrint("Entering into type.QueryInterface()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.QueryInterface,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&riid)), // A reference to the interface identifier (IID) of the interface being queried for.
+		uintptr(ppvObject),
+	)
+	if hr != S_OK {
+		return fmt.Errorf("the IUknown::QueryInterface method method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return nil
+}
+
+func (obj *Type) AddRef() uintptr {
+	ret, _, _ := syscall.SyscallN(
+		obj.vtbl.AddRef,
+		uintptr(unsafe.Pointer(obj)),
+	)
+	return ret
+}
+
+func (obj *Type) Release() uintptr {
+	ret, _, _ := syscall.SyscallN(
+		obj.vtbl.Release,
+		uintptr(unsafe.Pointer(obj)),
+	)
+	return ret
+}
+
+// getString calls a method that returns a BSTR and converts it
+func (obj *Type) getString(method uintptr, name string) (string, error) {
+	var bstr BSTR
+	hr, _, _ := syscall.SyscallN(
+		method,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&bstr)),
+	)
+	if hr != S_OK {
+		return "", fmt.Errorf("the Type::%s method returned a non-zero HRESULT: 0x%x", name, hr)
+	}
+	defer bstr.Free()
+	return bstr.String(), nil
+}
+
+// getBool calls a method that returns a VARIANT_BOOL and converts it
+func (obj *Type) getBool(method uintptr, name string) (bool, error) {
+	var b int16
+	hr, _, _ := syscall.SyscallN(
+		method,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&b)),
+	)
+	if hr != S_OK {
+		return false, fmt.Errorf("the Type::%s method returned a non-zero HRESULT: 0x%x", name, hr)
+	}
+	return b != 0, nil
+}
+
+// getArray calls a method that takes BindingFlags and returns a SAFEARRAY of interface pointers
+func (obj *Type) getArray(method uintptr, name string, bindingAttr BindingFlags) (*SafeArray, error) {
+	var psa *SafeArray
+	hr, _, _ := syscall.SyscallN(
+		method,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bindingAttr),
+		uintptr(unsafe.Pointer(&psa)),
+	)
+	if hr != S_OK {
+		return nil, fmt.Errorf("the Type::%s method returned a non-zero HRESULT: 0x%x", name, hr)
+	}
+	return psa, nil
+}
+
+// GetString returns a string that represents the current type, its full name
+//
+//	virtual HRESULT __stdcall get_ToString (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.tostring?view=net-5.0
+func (obj *Type) GetString() (string, error) {
+	debugPrint("Entering into type.GetString()...")
+	return obj.getString(obj.vtbl.get_ToString, "ToString")
+}
+
+// GetName returns the name of the type without its namespace
+//
+//	virtual HRESULT __stdcall get_name (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.reflection.memberinfo.name?view=net-5.0
+func (obj *Type) GetName() (string, error) {
+	debugPrint("Entering into type.GetName()...")
+	return obj.getString(obj.vtbl.get_name, "get_name")
+}
+
+// GetFullName returns the fully qualified name of the type, including its namespace but not its assembly
+//
+//	virtual HRESULT __stdcall get_FullName (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.fullname?view=net-5.0
+func (obj *Type) GetFullName() (string, error) {
+	debugPrint("Entering into type.GetFullName()...")
+	return obj.getString(obj.vtbl.get_FullName, "get_FullName")
+}
+
+// GetNamespace returns the namespace of the type
+//
+//	virtual HRESULT __stdcall get_Namespace (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.namespace?view=net-5.0
+func (obj *Type) GetNamespace() (string, error) {
+	debugPrint("Entering into type.GetNamespace()...")
+	return obj.getString(obj.vtbl.get_Namespace, "get_Namespace")
+}
+
+// GetAssemblyQualifiedName returns the assembly-qualified name of the type, which includes the name of the assembly
+// from which the type was loaded
+//
+//	virtual HRESULT __stdcall get_AssemblyQualifiedName (
+//	/*[out,retval]*/ BSTR * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.assemblyqualifiedname?view=net-5.0
+func (obj *Type) GetAssemblyQualifiedName() (string, error) {
+	debugPrint("Entering into type.GetAssemblyQualifiedName()...")
+	return obj.getString(obj.vtbl.get_AssemblyQualifiedName, "get_AssemblyQualifiedName")
+}
+
+// GetBaseType returns the type from which the current type directly inherits, nil for System.Object and interfaces
+//
+//	virtual HRESULT __stdcall get_BaseType (
+//	/*[out,retval]*/ struct _Type * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.basetype?view=net-5.0
+func (obj *Type) GetBaseType() (baseType *Type, err error) {
+	debugPrint("Entering into type.GetBaseType()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_BaseType,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&baseType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the Type::get_BaseType method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	return
+}
+
+// GetAssembly returns the assembly in which the type is declared
+//
+//	virtual HRESULT __stdcall get_Assembly (
+//	/*[out,retval]*/ struct _Assembly * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.assembly?view=net-5.0
+func (obj *Type) GetAssembly() (assembly *Assembly, err error) {
+	debugPrint("Entering into type.GetAssembly()...")
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.get_Assembly,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(unsafe.Pointer(&assembly)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the Type::get_Assembly method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
+
+// mscorlibType returns a type of mscorlib, the assembly of System.Object at the root of the hierarchy of the class t
+func mscorlibType(t *Type, name string) (*Type, error) {
+	t.AddRef()
+	for {
+		baseType, err := t.GetBaseType()
+		if err != nil {
+			t.Release()
+			return nil, err
+		}
+		if baseType == nil {
+			break
+		}
+		t.Release()
+		t = baseType
+	}
+	mscorlib, err := t.GetAssembly()
+	t.Release()
+	if err != nil {
+		return nil, err
+	}
+	defer mscorlib.Release()
+	return mscorlib.GetType_2(name)
+}
+
+// IsClass returns true if the type is a class or a delegate, that is, not a value type or interface
+//
+//	virtual HRESULT __stdcall get_IsClass (
+//	/*[out,retval]*/ VARIANT_BOOL * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.isclass?view=net-5.0
+func (obj *Type) IsClass() (bool, error) {
+	debugPrint("Entering into type.IsClass()...")
+	return obj.getBool(obj.vtbl.get_IsClass, "get_IsClass")
+}
+
+// IsInterface returns true if the type is an interface
+//
+//	virtual HRESULT __stdcall get_IsInterface (
+//	/*[out,retval]*/ VARIANT_BOOL * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.isinterface?view=net-5.0
+func (obj *Type) IsInterface() (bool, error) {
+	debugPrint("Entering into type.IsInterface()...")
+	return obj.getBool(obj.vtbl.get_IsInterface, "get_IsInterface")
+}
+
+// IsEnum returns true if the type is an enumeration
+//
+//	virtual HRESULT __stdcall get_IsEnum (
+//	/*[out,retval]*/ VARIANT_BOOL * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.isenum?view=net-5.0
+func (obj *Type) IsEnum() (bool, error) {
+	debugPrint("Entering into type.IsEnum()...")
+	return obj.getBool(obj.vtbl.get_IsEnum, "get_IsEnum")
+}
+
+// GetMethods searches for the methods of the type, using the specified binding constraints
+//
+//	virtual HRESULT __stdcall GetMethods (
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.getmethods?view=net-5.0
+func (obj *Type) GetMethods(bindingAttr BindingFlags) ([]*MethodInfo, error) {
+	debugPrint("Entering into type.GetMethods()...")
+	psa, err := obj.getArray(obj.vtbl.GetMethods, "GetMethods", bindingAttr)
+	if err != nil {
+		return nil, err
+	}
+	return interfacesFromSafeArray[MethodInfo](psa)
+}
+
+// GetMethod searches for the method with the specified name, using the specified binding constraints. An error is
+// returned when no method matches and the CLR returns an AmbiguousMatchException if the method is overloaded
+//
+//	virtual HRESULT __stdcall GetMethod_2 (
+//	/*[in]*/ BSTR name,
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[out,retval]*/ struct _MethodInfo * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.getmethod?view=net-5.0#System_Type_GetMethod_System_String_System_Reflection_BindingFlags_
+func (obj *Type) GetMethod(name string, bindingAttr BindingFlags) (method *MethodInfo, err error) {
+	debugPrint("Entering into type.GetMethod()...")
+	bstrName, err := SysAllocString(name)
+	if err != nil {
+		return
+	}
+	defer bstrName.Free()
+
+	hr, _, _ := syscall.SyscallN(
+		obj.vtbl.GetMethod_2,
+		uintptr(unsafe.Pointer(obj)),
+		uintptr(bstrName.Pointer()),
+		uintptr(bindingAttr),
+		uintptr(unsafe.Pointer(&method)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the Type::GetMethod_2 method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	if method == nil {
+		err = fmt.Errorf("the %s method was not found", name)
+	}
+	return
+}
+
+// GetProperties searches for the properties of the type, using the specified binding constraints
+//
+//	virtual HRESULT __stdcall GetProperties (
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.getproperties?view=net-5.0
+func (obj *Type) GetProperties(bindingAttr BindingFlags) ([]*MemberInfo, error) {
+	debugPrint("Entering into type.GetProperties()...")
+	psa, err := obj.getArray(obj.vtbl.GetProperties, "GetProperties", bindingAttr)
+	if err != nil {
+		return nil, err
+	}
+	return interfacesFromSafeArray[MemberInfo](psa)
+}
+
+// GetFields searches for the fields of the type, using the specified binding constraints
+//
+//	virtual HRESULT __stdcall GetFields (
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.getfields?view=net-5.0
+func (obj *Type) GetFields(bindingAttr BindingFlags) ([]*MemberInfo, error) {
+	debugPrint("Entering into type.GetFields()...")
+	psa, err := obj.getArray(obj.vtbl.GetFields, "GetFields", bindingAttr)
+	if err != nil {
+		return nil, err
+	}
+	return interfacesFromSafeArray[MemberInfo](psa)
+}
+
+// GetConstructors searches for the constructors of the type, using the specified binding constraints
+//
+//	virtual HRESULT __stdcall GetConstructors (
+//	/*[in]*/ enum BindingFlags bindingAttr,
+//	/*[out,retval]*/ SAFEARRAY * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.getconstructors?view=net-5.0
+func (obj *Type) GetConstructors(bindingAttr BindingFlags) ([]*MemberInfo, error) {
+	debugPrint("Entering into type.GetConstructors()...")
+	psa, err := obj.getArray(obj.vtbl.GetConstructors, "GetConstructors", bindingAttr)
+	if err != nil {
+		return nil, err
+	}
+	return interfacesFromSafeArray[MemberInfo](psa)
+}
+
+// InvokeMember invokes the specified member, using the specified binding constraints and matching the specified
+// argument list. invokeAttr selects the kind of member, such as BindingFlags_InvokeMethod or BindingFlags_GetProperty,
+// target is the object to invoke the member on, nil for static members, and args are converted with ToVariant.
+// The result is the member's return value converted with FromVariant, nil for void methods. An *IUnknown result
+// holds a reference that must be released by the caller
+//
+//	virtual HRESULT __stdcall InvokeMember_3 (
+//	/*[in]*/ BSTR name,
+//	/*[in]*/ enum BindingFlags invokeAttr,
+//	/*[in]*/ struct _Binder * Binder,
+//	/*[in]*/ VARIANT Target,
+//	/*[in]*/ SAFEARRAY * args,
+//	/*[out,retval]*/ VARIANT * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.type.invokemember?view=net-5.0#System_Type_InvokeMember_System_String_System_Reflection_BindingFlags_System_Reflection_Binder_System_Object_System_Object___
+func (obj *Type) InvokeMember(name string, invokeAttr BindingFlags, target any, args ...any) (result any, err error) {
+	debugPrint("Entering into type.InvokeMember()...")
+	bstrName, err := SysAllocString(name)
+	if err != nil {
+		return
+	}
+	defer bstrName.Free()
+
+	parameters, err := SafeArrayFromSlice(args)
+	if err != nil {
+		return
+	}
+	defer parameters.Destroy()
+
+	variantTarget, err := ToVariant(target)
+	if err != nil {
+		return
+	}
+	// A Variant is copied as is and still belongs to the caller
+	if _, borrowed := target.(Variant); !borrowed {
+		defer variantTarget.Clear()
+	}
+
+	// The VARIANT is passed by value following the calling convention of the architecture
+	var ret Variant
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	pinner.Pin(&ret)
+	callArgs := []uintptr{uintptr(unsafe.Pointer(obj)), uintptr(bstrName.Pointer()), uintptr(invokeAttr), 0}
+	callArgs = appendVariantArg(callArgs, &variantTarget, &pinner)
+	callArgs = append(callArgs, uintptr(unsafe.Pointer(parameters.SafeArray())), uintptr(unsafe.Pointer(&ret)))
+	hr, _, _ := syscall.SyscallN(obj.vtbl.InvokeMember_3, callArgs...)
+	if hr != S_OK {
+		err = fmt.Errorf("the Type::InvokeMember_3 method returned a non-zero HRESULT: 0x%x", hr)
+		return
+	}
+	return takeVariant(&ret)
+}
+
+// objectVtbl is the COM virtual table of the _Object interface that every managed object exposes
+// https://docs.microsoft.com/en-us/dotnet/api/system.object?view=net-5.0
+type objectVtbl struct {
+	QueryInterface   uintptr
+	AddRef           uintptr
+	Release          uintptr
+	GetTypeInfoCount uintptr
+	GetTypeInfo      uintptr
+	GetIDsOfNames    uintptr
+	Invoke           uintptr
+	get_ToString     uintptr
+	Equals           uintptr
+	GetHashCode      uintptr
+	GetType          uintptr
+}
+
+// GetType returns the Type of the managed object behind the interface pointer. The object is queried for its _Object
+// interface, so this fails for COM objects that are not managed objects
+//
+//	virtual HRESULT __stdcall GetType (
+//	/*[out,retval]*/ struct _Type * * pRetVal ) = 0;
+//
+// https://docs.microsoft.com/en-us/dotnet/api/system.object.gettype?view=net-5.0
+func (obj *IUnknown) GetType() (objectType *Type, err error) {
+	debugPrint("Entering into iunknown.GetType()...")
+	var object *struct{ vtbl *objectVtbl }
+	if err = obj.QueryInterface(IID__Object, unsafe.Pointer(&object)); err != nil {
+		return
+	}
+	defer (*IUnknown)(unsafe.Pointer(object)).Release()
+
+	hr, _, _ := syscall.SyscallN(
+		object.vtbl.GetType,
+		uintptr(unsafe.Pointer(object)),
+		uintptr(unsafe.Pointer(&objectType)),
+	)
+	if hr != S_OK {
+		err = fmt.Errorf("the Object::GetType method returned a non-zero HRESULT: 0x%x", hr)
+	}
+	return
+}
+
+// getProperty reads a public instance property of the managed object behind the interface pointer with late binding
+func (obj *IUnknown) getProperty(name string) (any, error) {
+	objectType, err := obj.GetType()
+	if err != nil {
+		return nil, err
+	}
+	defer objectType.Release()
+	return objectType.InvokeMember(name, BindingFlags_GetProperty|BindingFlags_Public|BindingFlags_Instance, obj)
+}
+
+// interfacesOf returns the non-null interface pointers of an array of objects converted by FromVariant, which is a
+// []*IUnknown for a SAFEARRAY of interfaces and a []any for a SAFEARRAY of VARIANTs
+func interfacesOf(value any) (interfaces []*IUnknown) {
+	switch value := value.(type) {
+	case []*IUnknown:
+		for _, element := range value {
+			if element != nil {
+				interfaces = append(interfaces, element)
+			}
+		}
+	case []any:
+		for _, element := range value {
+			if unknown, ok := element.(*IUnknown); ok && unknown != nil {
+				interfaces = append(interfaces, unknown)
+			}
+		}
+	}
+	return
+}
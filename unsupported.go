@@ -0,0 +1,152 @@
+//go:build !windows
+// +build !windows
+
+package clr
+
+import "unsafe"
+
+// oleAuto emulates OleAut32.dll so SAFEARRAYs, BSTRs and VARIANTs can be built and read on every platform
+var oleAuto oleAutomation = newOleAutomationEmulator()
+
+// ICLRMetaHost is only implemented on Windows
+type ICLRMetaHost struct{}
+
+// ICLRRuntimeInfo is only implemented on Windows
+type ICLRRuntimeInfo struct{}
+
+// ICLRRuntimeHost is only implemented on Windows
+type ICLRRuntimeHost struct{}
+
+// ICORRuntimeHost is only implemented on Windows
+type ICORRuntimeHost struct{}
+
+// AppDomain is only implemented on Windows
+type AppDomain struct{}
+
+// Assembly is only implemented on Windows
+type Assembly struct{}
+
+// MethodInfo is only implemented on Windows
+type MethodInfo struct{}
+
+// Type is only implemented on Windows
+type Type struct{}
+
+// MemberInfo is only implemented on Windows
+type MemberInfo struct{}
+
+// Object is only implemented on Windows
+type Object struct{}
+
+// ObjectHandle is only implemented on Windows
+type ObjectHandle struct{}
+
+// Subscription is only implemented on Windows
+type Subscription struct{}
+
+// DomainManager is only implemented on Windows
+type DomainManager struct{}
+
+// GetInstalledRuntimes returns ErrUnsupportedPlatform
+func GetInstalledRuntimes(metahost *ICLRMetaHost) ([]string, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// GetRuntimeInfo returns ErrUnsupportedPlatform
+func GetRuntimeInfo(metahost *ICLRMetaHost, version string) (*ICLRRuntimeInfo, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// GetICORRuntimeHost returns ErrUnsupportedPlatform
+func GetICORRuntimeHost(runtimeInfo *ICLRRuntimeInfo) (*ICORRuntimeHost, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// GetICLRRuntimeHost returns ErrUnsupportedPlatform
+func GetICLRRuntimeHost(runtimeInfo *ICLRRuntimeInfo) (*ICLRRuntimeHost, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// GetAppDomain returns ErrUnsupportedPlatform
+func GetAppDomain(runtimeHost *ICORRuntimeHost) (*AppDomain, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// ExecuteDLLFromDisk returns ErrUnsupportedPlatform
+func ExecuteDLLFromDisk(targetRuntime, dllpath, typeName, methodName, argument string) (retCode int16, err error) {
+	return -1, ErrUnsupportedPlatform
+}
+
+// ExecuteByteArray returns ErrUnsupportedPlatform
+func ExecuteByteArray(targetRuntime string, rawBytes []byte, params []string) (retCode int32, err error) {
+	return -1, ErrUnsupportedPlatform
+}
+
+// InvokeWithArgs returns ErrUnsupportedPlatform
+func InvokeWithArgs(method *MethodInfo, this any, args ...any) (result any, err error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// NewDomainManager returns ErrUnsupportedPlatform
+func NewDomainManager(runtimeHost *ICORRuntimeHost, name string, pool *DomainPool) (*DomainManager, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// NewObject returns ErrUnsupportedPlatform
+func NewObject(unknown *IUnknown) (*Object, error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// InvokeStatic returns ErrUnsupportedPlatform
+func InvokeStatic(assembly *Assembly, typeName, methodName string, args ...any) (result any, err error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// LoadLibraryAssembly returns ErrUnsupportedPlatform
+func LoadLibraryAssembly(runtimeHost *ICORRuntimeHost, rawBytes []byte) (assembly *Assembly, err error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// LoadCLR returns ErrUnsupportedPlatform
+func LoadCLR(targetRuntime string) (runtimeHost *ICORRuntimeHost, err error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// ExecuteByteArrayDefaultDomain reports ErrUnsupportedPlatform on stderr
+func ExecuteByteArrayDefaultDomain(runtimeHost *ICORRuntimeHost, rawBytes []byte, params []string) (stdout string, stderr string, retCode int32) {
+	return "", ErrUnsupportedPlatform.Error(), -1
+}
+
+// LoadAssembly returns ErrUnsupportedPlatform
+func LoadAssembly(runtimeHost *ICORRuntimeHost, rawBytes []byte) (methodInfo *MethodInfo, err error) {
+	return nil, ErrUnsupportedPlatform
+}
+
+// InvokeAssembly reports ErrUnsupportedPlatform on stderr
+func InvokeAssembly(methodInfo *MethodInfo, params []string) (stdout string, stderr string, result any) {
+	return "", ErrUnsupportedPlatform.Error(), nil
+}
+
+// RedirectStdoutStderr returns ErrUnsupportedPlatform
+func RedirectStdoutStderr() error {
+	return ErrUnsupportedPlatform
+}
+
+// RestoreStdoutStderr returns ErrUnsupportedPlatform
+func RestoreStdoutStderr() error {
+	return ErrUnsupportedPlatform
+}
+
+// comAddRef increments the reference count of a DispatchObject, other interface pointers can not exist off Windows
+func comAddRef(p unsafe.Pointer) {
+	if obj, ok := liveDispatchObject(p); ok {
+		obj.AddRef()
+	}
+}
+
+// comRelease decrements the reference count of a DispatchObject
+func comRelease(p unsafe.Pointer) {
+	if obj, ok := liveDispatchObject(p); ok {
+		obj.Release()
+	}
+}
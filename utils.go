@@ -1,31 +1,37 @@
-//go:build windows
-// +build windows
-
 package clr
 
 import (
-	"bytes"
+	"errors"
 	"fmt"
 	"strings"
 	"unicode/utf16"
 	"unsafe"
-
-	"golang.org/x/text/encoding/unicode"
-	"golang.org/x/text/transform"
 )
 
 var Debug = false
 
-func utf16Le(s string) []byte {
-	enc := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder()
-	var buf bytes.Buffer
-	t := transform.NewWriter(&buf, enc)
-	t.Write([]byte(s))
-	return buf.Bytes()
+// ErrUnsupportedPlatform is returned by every function that needs to host the CLR when the package is built for a
+// platform other than Windows
+var ErrUnsupportedPlatform = errors.New("hosting the CLR is only supported on Windows")
+
+// exitCode returns the value returned by an assembly's entry point, which is either an int or void
+func exitCode(result any) (int32, error) {
+	switch ret := result.(type) {
+	case nil:
+		return 0, nil
+	case int32:
+		return ret, nil
+	}
+	return -1, fmt.Errorf("the entry point returned a %T instead of an int", result)
 }
 
-func expectsParams(input string) bool {
-	return !strings.Contains(input, "Void Main()")
+// argTypes lists the Go types of the arguments of a call for error messages, such as "string, int32, <nil>"
+func argTypes(args []any) string {
+	types := make([]string, len(args))
+	for i, arg := range args {
+		types[i] = fmt.Sprintf("%T", arg)
+	}
+	return strings.Join(types, ", ")
 }
 
 // ReadUnicodeStr takes a pointer to a unicode string in memory and returns a string value
@@ -54,38 +60,22 @@ func debugPrint(message string) {
 // PrepareParameters creates a safe array of strings (arguments) nested inside a Variant object, which is itself
 // appended to the final safe array
 func PrepareParameters(params []string) (*SafeArray, error) {
-	sab := SafeArrayBound{
-		cElements: uint32(len(params)),
-		lLbound:   0,
-	}
-	listStrSafeArrayPtr, err := SafeArrayCreate(VT_BSTR, 1, &sab) // VT_BSTR
+	listStr, err := SafeArrayFromSlice(params)
 	if err != nil {
 		return nil, err
 	}
-	for i, p := range params {
-		bstr, err := SysAllocString(p)
-		if err != nil {
-			return nil, err
-		}
-		SafeArrayPutElement(listStrSafeArrayPtr, int32(i), bstr)
-	}
-
-	paramVariant := Variant{
-		VT:  VT_BSTR | VT_ARRAY, // VT_BSTR | VT_ARRAY
-		Val: uintptr(unsafe.Pointer(listStrSafeArrayPtr)),
-	}
+	// The VT_BSTR | VT_ARRAY Variant takes ownership of the string array
+	paramVariant := listStr.Variant()
+	defer paramVariant.Clear()
 
-	sab2 := SafeArrayBound{
-		cElements: uint32(1),
-		lLbound:   0,
-	}
-	paramsSafeArrayPtr, err := SafeArrayCreate(VT_VARIANT, 1, &sab2) // VT_VARIANT
+	paramsSafeArray, err := NewSafeArrayOf[any](NewSafeArrayBound(0, 1))
 	if err != nil {
 		return nil, err
 	}
-	err = SafeArrayPutElement(paramsSafeArrayPtr, int32(0), unsafe.Pointer(&paramVariant))
-	if err != nil {
+	// SafeArrayPutElement copies the Variant along with the string array
+	if err = paramsSafeArray.Put(paramVariant, 0); err != nil {
+		paramsSafeArray.Destroy()
 		return nil, err
 	}
-	return paramsSafeArrayPtr, nil
+	return paramsSafeArray.SafeArray(), nil
 }
@@ -1,7 +1,14 @@
-// +build windows
-
 package clr
 
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"time"
+	"unsafe"
+)
+
 const (
 	// VT_EMPTY No value was specified. If an optional argument to an Automation method is left blank, do not
 	// pass a VARIANT of type VT_EMPTY. Instead, pass a VARIANT of type VT_ERROR with a value of DISP_E_PARAMNOTFOUND.
@@ -9,30 +16,613 @@ const (
 	// VT_NULL A propagating null value was specified. (This should not be confused with the null pointer.)
 	// The null value is used for tri-state logic, as with SQL.
 	VT_NULL uint16 = 0x0001
-	// VT_UI1 is a Variant Type of Unsigned Integer of 1-byte
-	VT_UI1 uint16 = 0x0011
-	// VT_UT4 is a Varriant Type of Unsigned Integer of 4-byte
-	VT_UI4 uint16 = 0x0013
+	// VT_I2 is a Variant Type of Signed Integer of 2-bytes
+	VT_I2 uint16 = 0x0002
+	// VT_I4 is a Variant Type of Signed Integer of 4-bytes
+	VT_I4 uint16 = 0x0003
+	// VT_R4 is a Variant Type of a 4-byte IEEE floating point number
+	VT_R4 uint16 = 0x0004
+	// VT_R8 is a Variant Type of an 8-byte IEEE floating point number
+	VT_R8 uint16 = 0x0005
+	// VT_CY is a Variant Type of Currency, a 64-bit integer scaled by 10,000
+	VT_CY uint16 = 0x0006
+	// VT_DATE is a Variant Type of an OLE Automation date, the number of days since midnight, 30 December 1899
+	VT_DATE uint16 = 0x0007
 	// VT_BSTR is a Variant Type of BSTR, an OLE automation type for transfering length-prefixed strings
 	// https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-oaut/9c5a5ce4-ff5b-45ce-b915-ada381b34ac1
 	VT_BSTR uint16 = 0x0008
+	// VT_DISPATCH is a Variant Type of an IDispatch interface pointer
+	VT_DISPATCH uint16 = 0x0009
+	// VT_ERROR is a Variant Type of an SCODE status code
+	VT_ERROR uint16 = 0x000a
+	// VT_BOOL is a Variant Type of VARIANT_BOOL where -1 is true and 0 is false
+	VT_BOOL uint16 = 0x000b
 	// VT_VARIANT is a Variant Type of VARIANT, a container for a union that can hold many types of data
 	VT_VARIANT uint16 = 0x000c
+	// VT_UNKNOWN is a Variant Type of an IUnknown interface pointer
+	VT_UNKNOWN uint16 = 0x000d
+	// VT_DECIMAL is a Variant Type of DECIMAL, a 96-bit integer with a sign and a power of ten scale
+	VT_DECIMAL uint16 = 0x000e
+	// VT_I1 is a Variant Type of Signed Integer of 1-byte
+	VT_I1 uint16 = 0x0010
+	// VT_UI1 is a Variant Type of Unsigned Integer of 1-byte
+	VT_UI1 uint16 = 0x0011
+	// VT_UI2 is a Variant Type of Unsigned Integer of 2-bytes
+	VT_UI2 uint16 = 0x0012
+	// VT_UT4 is a Varriant Type of Unsigned Integer of 4-byte
+	VT_UI4 uint16 = 0x0013
+	// VT_I8 is a Variant Type of Signed Integer of 8-bytes
+	VT_I8 uint16 = 0x0014
+	// VT_UI8 is a Variant Type of Unsigned Integer of 8-bytes
+	VT_UI8 uint16 = 0x0015
+	// VT_INT is a Variant Type of a machine signed integer, always 4-bytes on Windows
+	VT_INT uint16 = 0x0016
+	// VT_UINT is a Variant Type of a machine unsigned integer, always 4-bytes on Windows
+	VT_UINT uint16 = 0x0017
 	// VT_ARRAY is a Variant Type of a SAFEARRAY
 	// https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-oaut/2e87a537-9305-41c6-a88b-b79809b3703a
 	VT_ARRAY uint16 = 0x2000
+	// VT_BYREF is a Variant Type flag indicating the VARIANT holds a pointer to the value instead of the value
+	VT_BYREF uint16 = 0x4000
+	// VT_TYPEMASK masks off the VT_ARRAY and VT_BYREF flags
+	VT_TYPEMASK uint16 = 0x0fff
 )
 
-// from https://github.com/go-ole/go-ole/blob/master/variant_amd64.go
-// https://docs.microsoft.com/en-us/windows/win32/winauto/variant-structure
-// https://docs.microsoft.com/en-us/windows/win32/api/oaidl/ns-oaidl-variant
-// https://docs.microsoft.com/en-us/previous-versions/windows/embedded/ms891678(v=msdn.10)?redirectedfrom=MSDN
-// VARIANT Type Constants https://docs.microsoft.com/en-us/openspecs/windows_protocols/ms-oaut/3fe7db9f-5803-4dc4-9d14-5425d3f5461f
-type Variant struct {
-	VT         uint16 // VARTYPE
-	wReserved1 uint16
-	wReserved2 uint16
-	wReserved3 uint16
-	Val        uintptr
-	_          [8]byte
+// Currency is the VT_CY fixed point type, a 64-bit integer scaled by 10,000
+type Currency int64
+
+// Date is the VT_DATE type, the number of days since midnight, 30 December 1899. The fractional part is the time of day
+type Date float64
+
+// SCode is the VT_ERROR type, a COM status code. Pass SCode(DISP_E_PARAMNOTFOUND) for an omitted optional argument
+type SCode uint32
+
+// Decimal is the VT_DECIMAL type, a 96-bit unsigned integer with a sign and a power of ten scale from 0 to 28
+//
+//	typedef struct tagDEC {
+//	  USHORT wReserved;
+//	  BYTE   scale;
+//	  BYTE   sign;
+//	  ULONG  Hi32;
+//	  ULONGLONG Lo64;
+//	} DECIMAL;
+//
+// https://docs.microsoft.com/en-us/windows/win32/api/wtypes/ns-wtypes-decimal-r1
+type Decimal struct {
+	wReserved uint16
+	// Scale is the power of ten the 96-bit integer is divided by
+	Scale byte
+	// Sign is 0x80 for negative numbers and 0 otherwise
+	Sign byte
+	// Hi32 is the high 32 bits of the 96-bit integer
+	Hi32 uint32
+	// Lo64 is the low 64 bits of the 96-bit integer
+	Lo64 uint64
+}
+
+// DECIMAL_NEG is the Decimal Sign of a negative number
+const DECIMAL_NEG byte = 0x80
+
+// val returns a pointer to the value union of the VARIANT
+func (v *Variant) val() unsafe.Pointer {
+	return unsafe.Pointer(&v.Val)
+}
+
+// ptr returns the pointer stored in the value union of the VARIANT
+func (v *Variant) ptr() unsafe.Pointer {
+	return *(*unsafe.Pointer)(v.val())
+}
+
+// setPtr stores a pointer in the value union of the VARIANT
+func (v *Variant) setPtr(p unsafe.Pointer) {
+	*(*unsafe.Pointer)(v.val()) = p
+}
+
+// Clear releases the resources held by the VARIANT, strings, arrays and interface pointers, through VariantClear
+// and sets it to VT_EMPTY
+func (v *Variant) Clear() error {
+	return VariantClear(v)
+}
+
+// variantTypes maps Go types to the Variant Type used for them as SAFEARRAY elements and VT_BYREF targets
+var variantTypes = map[reflect.Type]uint16{
+	reflect.TypeOf(int8(0)):                VT_I1,
+	reflect.TypeOf(int16(0)):               VT_I2,
+	reflect.TypeOf(int32(0)):               VT_I4,
+	reflect.TypeOf(int64(0)):               VT_I8,
+	reflect.TypeOf(uint8(0)):               VT_UI1,
+	reflect.TypeOf(uint16(0)):              VT_UI2,
+	reflect.TypeOf(uint32(0)):              VT_UI4,
+	reflect.TypeOf(uint64(0)):              VT_UI8,
+	reflect.TypeOf(float32(0)):             VT_R4,
+	reflect.TypeOf(float64(0)):             VT_R8,
+	reflect.TypeOf(false):                  VT_BOOL,
+	reflect.TypeOf(""):                     VT_BSTR,
+	reflect.TypeOf(Currency(0)):            VT_CY,
+	reflect.TypeOf(Date(0)):                VT_DATE,
+	reflect.TypeOf(SCode(0)):               VT_ERROR,
+	reflect.TypeOf(Decimal{}):              VT_DECIMAL,
+	reflect.TypeOf((*IUnknown)(nil)):       VT_UNKNOWN,
+	reflect.TypeOf((*DispatchObject)(nil)): VT_DISPATCH,
+	reflect.TypeOf((*any)(nil)).Elem():     VT_VARIANT,
+}
+
+// variantGoTypes maps a Variant Type to the Go type FromVariant produces for it
+var variantGoTypes = map[uint16]reflect.Type{
+	VT_I1:       reflect.TypeOf(int8(0)),
+	VT_I2:       reflect.TypeOf(int16(0)),
+	VT_I4:       reflect.TypeOf(int32(0)),
+	VT_INT:      reflect.TypeOf(int32(0)),
+	VT_I8:       reflect.TypeOf(int64(0)),
+	VT_UI1:      reflect.TypeOf(uint8(0)),
+	VT_UI2:      reflect.TypeOf(uint16(0)),
+	VT_UI4:      reflect.TypeOf(uint32(0)),
+	VT_UINT:     reflect.TypeOf(uint32(0)),
+	VT_UI8:      reflect.TypeOf(uint64(0)),
+	VT_R4:       reflect.TypeOf(float32(0)),
+	VT_R8:       reflect.TypeOf(float64(0)),
+	VT_BOOL:     reflect.TypeOf(false),
+	VT_BSTR:     reflect.TypeOf(""),
+	VT_CY:       reflect.TypeOf(Currency(0)),
+	VT_DATE:     reflect.TypeOf(Date(0)),
+	VT_ERROR:    reflect.TypeOf(SCode(0)),
+	VT_DECIMAL:  reflect.TypeOf(Decimal{}),
+	VT_UNKNOWN:  reflect.TypeOf((*IUnknown)(nil)),
+	VT_DISPATCH: reflect.TypeOf((*IUnknown)(nil)),
+	VT_VARIANT:  reflect.TypeOf((*any)(nil)).Elem(),
+}
+
+// comObject is implemented by the wrappers of the COM interfaces returned by the CLR, a pointer to the wrapper is the
+// interface pointer
+type comObject interface {
+	AddRef() uintptr
+	Release() uintptr
+}
+
+// managedObject is implemented by the Go handles of managed objects, such as *Object, that hold an interface pointer
+type managedObject interface {
+	Unknown() *IUnknown
+}
+
+// ToVariant converts a Go value to a VARIANT. The returned VARIANT owns any string, array or interface reference it
+// holds and must be released with Clear once it is no longer needed.
+//
+//   - nil is VT_EMPTY, which the CLR receives as a null reference
+//   - bool, int8-64, uint8-64, float32/64 map to VT_BOOL, VT_I1-I8, VT_UI1-UI8, VT_R4/R8. int and uint are VT_I4 and
+//     VT_UI4 when the value fits and VT_I8 and VT_UI8 otherwise
+//   - string is VT_BSTR; Currency, Date, Decimal and SCode are VT_CY, VT_DATE, VT_DECIMAL and VT_ERROR
+//   - time.Time is VT_DATE, converted with NewDate, and a *big.Rat is VT_DECIMAL, converted with DecimalFromRat
+//   - *IUnknown is VT_UNKNOWN and *DispatchObject is VT_DISPATCH, a new reference is taken on the interface. The
+//     wrappers of managed objects obtained from the CLR, such as *Assembly and *MethodInfo, and *Object handles are
+//     VT_UNKNOWN too
+//   - a slice is a one dimensional VT_ARRAY of the element type, []any is an array of VT_VARIANT
+//   - a pointer to a numeric type, Currency, Date, SCode or Variant is VT_BYREF. The pointed to memory is not copied
+//     and must stay alive for as long as the VARIANT is in use
+//   - a Variant is copied as is, it still belongs to the caller and is not owned by the result
+func ToVariant(value any) (Variant, error) {
+	var v Variant
+	switch x := value.(type) {
+	case nil:
+		v.VT = VT_EMPTY
+	case Variant:
+		v = x
+	case bool:
+		v.VT = VT_BOOL
+		*(*int16)(v.val()) = variantBool(x)
+	case int8:
+		v.VT = VT_I1
+		*(*int8)(v.val()) = x
+	case int16:
+		v.VT = VT_I2
+		*(*int16)(v.val()) = x
+	case int32:
+		v.VT = VT_I4
+		*(*int32)(v.val()) = x
+	case int64:
+		v.VT = VT_I8
+		*(*int64)(v.val()) = x
+	case int:
+		if x >= math.MinInt32 && x <= math.MaxInt32 {
+			v.VT = VT_I4
+			*(*int32)(v.val()) = int32(x)
+		} else {
+			v.VT = VT_I8
+			*(*int64)(v.val()) = int64(x)
+		}
+	case uint8:
+		v.VT = VT_UI1
+		*(*uint8)(v.val()) = x
+	case uint16:
+		v.VT = VT_UI2
+		*(*uint16)(v.val()) = x
+	case uint32:
+		v.VT = VT_UI4
+		*(*uint32)(v.val()) = x
+	case uint64:
+		v.VT = VT_UI8
+		*(*uint64)(v.val()) = x
+	case uint:
+		if uint64(x) <= math.MaxUint32 {
+			v.VT = VT_UI4
+			*(*uint32)(v.val()) = uint32(x)
+		} else {
+			v.VT = VT_UI8
+			*(*uint64)(v.val()) = uint64(x)
+		}
+	case float32:
+		v.VT = VT_R4
+		*(*float32)(v.val()) = x
+	case float64:
+		v.VT = VT_R8
+		*(*float64)(v.val()) = x
+	case Currency:
+		v.VT = VT_CY
+		*(*Currency)(v.val()) = x
+	case Date:
+		v.VT = VT_DATE
+		*(*Date)(v.val()) = x
+	case SCode:
+		v.VT = VT_ERROR
+		*(*SCode)(v.val()) = x
+	case Decimal:
+		// The DECIMAL overlays the whole VARIANT, its reserved field is the VARTYPE
+		*(*Decimal)(unsafe.Pointer(&v)) = x
+		v.VT = VT_DECIMAL
+	case time.Time:
+		d, err := NewDate(x)
+		if err != nil {
+			return v, err
+		}
+		v.VT = VT_DATE
+		*(*Date)(v.val()) = d
+	case *big.Rat:
+		if x == nil {
+			break
+		}
+		d, err := DecimalFromRat(x)
+		if err != nil {
+			return v, err
+		}
+		*(*Decimal)(unsafe.Pointer(&v)) = d
+		v.VT = VT_DECIMAL
+	case string:
+		bstr, err := SysAllocString(x)
+		if err != nil {
+			return v, err
+		}
+		v.VT = VT_BSTR
+		v.setPtr(bstr.Pointer())
+	case *IUnknown:
+		v.VT = VT_UNKNOWN
+		if x != nil {
+			comAddRef(unsafe.Pointer(x))
+			v.setPtr(unsafe.Pointer(x))
+		}
+	case *DispatchObject:
+		v.VT = VT_DISPATCH
+		if x != nil {
+			x.AddRef()
+			v.setPtr(unsafe.Pointer(x))
+		}
+	case *Variant:
+		v.VT = VT_VARIANT | VT_BYREF
+		v.setPtr(unsafe.Pointer(x))
+	case managedObject:
+		v.VT = VT_UNKNOWN
+		if unknown := x.Unknown(); unknown != nil {
+			comAddRef(unsafe.Pointer(unknown))
+			v.setPtr(unsafe.Pointer(unknown))
+		}
+	case comObject:
+		v.VT = VT_UNKNOWN
+		if rv := reflect.ValueOf(x); rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			x.AddRef()
+			v.setPtr(rv.UnsafePointer())
+		}
+	default:
+		return byRefOrArrayVariant(value)
+	}
+	return v, nil
+}
+
+// byRefOrArrayVariant converts pointers to VT_BYREF and slices to VT_ARRAY variants
+func byRefOrArrayVariant(value any) (Variant, error) {
+	var v Variant
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		vt, ok := variantTypes[rv.Type().Elem()]
+		if !ok || vt == VT_BOOL || vt == VT_BSTR || vt == VT_DECIMAL || vt == VT_VARIANT || vt == VT_UNKNOWN || vt == VT_DISPATCH {
+			return v, fmt.Errorf("a pointer to %s can not be passed by reference in a VARIANT", rv.Type().Elem())
+		}
+		v.VT = vt | VT_BYREF
+		v.setPtr(rv.UnsafePointer())
+		return v, nil
+	case reflect.Slice:
+		psa, vt, err := safeArrayFromSlice(rv)
+		if err != nil {
+			return v, err
+		}
+		v.VT = vt | VT_ARRAY
+		v.setPtr(unsafe.Pointer(psa))
+		return v, nil
+	}
+	return v, fmt.Errorf("the %T type can not be converted to a VARIANT", value)
+}
+
+// safeArrayFromSlice creates a one dimensional SAFEARRAY from a Go slice. Slices of types without a matching Variant
+// Type, including nested slices, become arrays of VT_VARIANT
+func safeArrayFromSlice(rv reflect.Value) (*SafeArray, uint16, error) {
+	vt, ok := variantTypes[rv.Type().Elem()]
+	if !ok {
+		vt = VT_VARIANT
+	}
+	bound := SafeArrayBound{cElements: uint32(rv.Len())}
+	psa, err := SafeArrayCreate(vt, 1, &bound)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := 0; i < rv.Len(); i++ {
+		value := rv.Index(i).Interface()
+		elem, err := ToVariant(value)
+		if err == nil {
+			err = putVariantElement(psa, int32(i), vt, &elem)
+			// A Variant is borrowed from the caller rather than created by ToVariant
+			if _, borrowed := value.(Variant); !borrowed {
+				elem.Clear()
+			}
+		}
+		if err != nil {
+			SafeArrayDestroy(psa)
+			return nil, 0, fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	return psa, vt, nil
+}
+
+// putVariantElement stores the value of a VARIANT in a SAFEARRAY of type vt. SafeArrayPutElement takes strings
+// and interface pointers directly and copies them, every other type is passed as a pointer to the value
+func putVariantElement(psa *SafeArray, index int32, vt uint16, elem *Variant) error {
+	switch {
+	case vt == VT_VARIANT:
+		return SafeArrayPutElement(psa, []int32{index}, unsafe.Pointer(elem))
+	case elem.VT != vt:
+		return fmt.Errorf("a VARIANT of type 0x%x can not be stored in a SAFEARRAY of type 0x%x", elem.VT, vt)
+	case vt == VT_BSTR || vt == VT_UNKNOWN || vt == VT_DISPATCH:
+		return SafeArrayPutElement(psa, []int32{index}, elem.ptr())
+	case vt == VT_DECIMAL:
+		d := *(*Decimal)(unsafe.Pointer(elem))
+		d.wReserved = 0
+		return SafeArrayPutElement(psa, []int32{index}, unsafe.Pointer(&d))
+	}
+	return SafeArrayPutElement(psa, []int32{index}, elem.val())
+}
+
+// FromVariant converts a VARIANT to a Go value using the mapping described by ToVariant. VT_EMPTY and VT_NULL are
+// nil, VT_INT and VT_UINT are int32 and uint32, VT_UNKNOWN and VT_DISPATCH are an *IUnknown and one dimensional
+// arrays are slices of the element type. VT_BYREF values are dereferenced.
+// The VARIANT is not modified and the caller still owns it. Interface pointers are borrowed from the VARIANT, call
+// AddRef on them to keep them after the VARIANT is cleared
+func FromVariant(v *Variant) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	if v.VT&VT_BYREF != 0 {
+		return fromByRefVariant(v)
+	}
+	if v.VT&VT_ARRAY != 0 {
+		return sliceFromSafeArray((*SafeArray)(v.ptr()), v.VT&VT_TYPEMASK)
+	}
+
+	switch v.VT {
+	case VT_EMPTY, VT_NULL:
+		return nil, nil
+	case VT_BOOL:
+		return *(*int16)(v.val()) != 0, nil
+	case VT_I1:
+		return *(*int8)(v.val()), nil
+	case VT_I2:
+		return *(*int16)(v.val()), nil
+	case VT_I4, VT_INT:
+		return *(*int32)(v.val()), nil
+	case VT_I8:
+		return *(*int64)(v.val()), nil
+	case VT_UI1:
+		return *(*uint8)(v.val()), nil
+	case VT_UI2:
+		return *(*uint16)(v.val()), nil
+	case VT_UI4, VT_UINT:
+		return *(*uint32)(v.val()), nil
+	case VT_UI8:
+		return *(*uint64)(v.val()), nil
+	case VT_R4:
+		return *(*float32)(v.val()), nil
+	case VT_R8:
+		return *(*float64)(v.val()), nil
+	case VT_CY:
+		return *(*Currency)(v.val()), nil
+	case VT_DATE:
+		return *(*Date)(v.val()), nil
+	case VT_ERROR:
+		return *(*SCode)(v.val()), nil
+	case VT_DECIMAL:
+		d := *(*Decimal)(unsafe.Pointer(v))
+		d.wReserved = 0
+		return d, nil
+	case VT_BSTR:
+		return BSTR{v.ptr()}.String(), nil
+	case VT_UNKNOWN, VT_DISPATCH:
+		return (*IUnknown)(v.ptr()), nil
+	}
+	return nil, fmt.Errorf("the VARIANT type 0x%x is not supported", v.VT)
+}
+
+// takeVariant converts a VARIANT the caller owns with FromVariant and releases it. A VT_UNKNOWN or VT_DISPATCH
+// reference is handed over to the returned *IUnknown instead of being released and every *IUnknown read from an array
+// holds a new reference, so the returned interfaces all belong to the caller
+func takeVariant(v *Variant) (any, error) {
+	value, err := FromVariant(v)
+	if err != nil {
+		v.Clear()
+		return nil, err
+	}
+	if v.VT != VT_UNKNOWN && v.VT != VT_DISPATCH {
+		retainInterfaces(value)
+		if err = v.Clear(); err != nil {
+			return nil, err
+		}
+	}
+	return value, nil
+}
+
+// retainInterfaces takes a new reference on the *IUnknown elements of a slice converted by FromVariant, including
+// those of nested slices, because the references held by the SAFEARRAY are released with it
+func retainInterfaces(value any) {
+	switch value := value.(type) {
+	case []*IUnknown:
+		for _, element := range value {
+			if element != nil {
+				comAddRef(unsafe.Pointer(element))
+			}
+		}
+	case []any:
+		for _, element := range value {
+			if unknown, ok := element.(*IUnknown); ok && unknown != nil {
+				comAddRef(unsafe.Pointer(unknown))
+			} else {
+				retainInterfaces(element)
+			}
+		}
+	}
+}
+
+// releaseInterfaces releases the *IUnknown references held by a value returned by takeVariant, including those of the
+// elements of slices
+func releaseInterfaces(value any) {
+	switch value := value.(type) {
+	case *IUnknown:
+		if value != nil {
+			comRelease(unsafe.Pointer(value))
+		}
+	case []*IUnknown:
+		for _, element := range value {
+			releaseInterfaces(element)
+		}
+	case []any:
+		for _, element := range value {
+			releaseInterfaces(element)
+		}
+	}
+}
+
+// fromByRefVariant dereferences a VT_BYREF VARIANT and converts the value it points to
+func fromByRefVariant(v *Variant) (any, error) {
+	p := v.ptr()
+	if p == nil {
+		return nil, fmt.Errorf("the VT_BYREF VARIANT of type 0x%x holds a null pointer", v.VT)
+	}
+	vt := v.VT &^ VT_BYREF
+	switch {
+	case vt == VT_VARIANT:
+		return FromVariant((*Variant)(p))
+	case vt == VT_DECIMAL:
+		deref := *(*Variant)(p)
+		deref.VT = VT_DECIMAL
+		return FromVariant(&deref)
+	}
+	size, err := variantElementSize(vt)
+	if err != nil {
+		return nil, err
+	}
+	deref := Variant{VT: vt}
+	copy(unsafe.Slice((*byte)(deref.val()), size), unsafe.Slice((*byte)(p), size))
+	return FromVariant(&deref)
+}
+
+// sliceFromSafeArray copies a one dimensional SAFEARRAY of type vt into a Go slice
+func sliceFromSafeArray(psa *SafeArray, vt uint16) (any, error) {
+	if psa == nil {
+		return nil, nil
+	}
+	goType, ok := variantGoTypes[vt]
+	if !ok {
+		return nil, fmt.Errorf("SAFEARRAYs of VARIANT type 0x%x are not supported", vt)
+	}
+	dims, err := SafeArrayGetDim(psa)
+	if err != nil {
+		return nil, err
+	}
+	if dims != 1 {
+		return nil, fmt.Errorf("the SAFEARRAY has %d dimensions, use WrapSafeArray to read it", dims)
+	}
+	size, err := variantElementSize(vt)
+	if err != nil {
+		return nil, err
+	}
+	count := int(psa.rgsabound[0].cElements)
+
+	data, err := SafeArrayAccessData(psa)
+	if err != nil {
+		return nil, err
+	}
+	defer SafeArrayUnaccessData(psa)
+
+	if vt == VT_UI1 {
+		return append([]byte(nil), unsafe.Slice((*byte)(data), count)...), nil
+	}
+
+	out := reflect.MakeSlice(reflect.SliceOf(goType), count, count)
+	for i := 0; i < count; i++ {
+		elem := unsafe.Add(data, i*size)
+		var value any
+		if vt == VT_VARIANT {
+			value, err = FromVariant((*Variant)(elem))
+		} else {
+			v := Variant{VT: vt}
+			if vt == VT_DECIMAL {
+				*(*Decimal)(unsafe.Pointer(&v)) = *(*Decimal)(elem)
+				v.VT = VT_DECIMAL
+			} else {
+				copy(unsafe.Slice((*byte)(v.val()), size), unsafe.Slice((*byte)(elem), size))
+			}
+			value, err = FromVariant(&v)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		if value != nil {
+			out.Index(i).Set(reflect.ValueOf(value))
+		}
+	}
+	return out.Interface(), nil
+}
+
+// variantElementSize returns the size in bytes of a value of type vt when stored in a SAFEARRAY or pointed to by a
+// VT_BYREF VARIANT
+func variantElementSize(vt uint16) (int, error) {
+	switch vt {
+	case VT_I1, VT_UI1:
+		return 1, nil
+	case VT_I2, VT_UI2, VT_BOOL:
+		return 2, nil
+	case VT_I4, VT_UI4, VT_INT, VT_UINT, VT_R4, VT_ERROR:
+		return 4, nil
+	case VT_I8, VT_UI8, VT_R8, VT_CY, VT_DATE:
+		return 8, nil
+	case VT_BSTR, VT_UNKNOWN, VT_DISPATCH:
+		return int(unsafe.Sizeof(uintptr(0))), nil
+	case VT_DECIMAL:
+		return int(unsafe.Sizeof(Decimal{})), nil
+	case VT_VARIANT:
+		return int(unsafe.Sizeof(Variant{})), nil
+	}
+	return 0, fmt.Errorf("the VARIANT type 0x%x has no fixed size", vt)
+}
+
+// variantBool converts a Go bool to a VARIANT_BOOL
+func variantBool(b bool) int16 {
+	if b {
+		return -1
+	}
+	return 0
 }
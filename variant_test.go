@@ -0,0 +1,255 @@
+package clr
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"unsafe"
+)
+
+// checkEmulatorAllocations fails the test if it leaks arrays or strings when the OLE Automation functions are emulated
+func checkEmulatorAllocations(t *testing.T) {
+	emulator, emulated := oleAuto.(*oleAutomationEmulator)
+	if !emulated {
+		return
+	}
+	arrays, strings := len(emulator.arrays), len(emulator.strings)
+	t.Cleanup(func() {
+		if len(emulator.arrays) != arrays || len(emulator.strings) != strings {
+			t.Errorf("the test leaked %d arrays and %d strings", len(emulator.arrays)-arrays, len(emulator.strings)-strings)
+		}
+	})
+}
+
+// variantCase is a value converted by ToVariant, the type of the VARIANT and the value FromVariant converts it back to
+type variantCase struct {
+	value any
+	vt    uint16
+	want  any
+}
+
+func TestVariantRoundTrip(t *testing.T) {
+	checkEmulatorAllocations(t)
+	decimal, _ := NewDecimal("-79228162514264337593543950.335")
+	tests := []variantCase{
+		{nil, VT_EMPTY, nil},
+		{true, VT_BOOL, true},
+		{false, VT_BOOL, false},
+		{int8(math.MinInt8), VT_I1, int8(math.MinInt8)},
+		{int16(math.MinInt16), VT_I2, int16(math.MinInt16)},
+		{int32(math.MinInt32), VT_I4, int32(math.MinInt32)},
+		{int64(math.MinInt64), VT_I8, int64(math.MinInt64)},
+		{int(-42), VT_I4, int32(-42)},
+		{uint8(math.MaxUint8), VT_UI1, uint8(math.MaxUint8)},
+		{uint16(math.MaxUint16), VT_UI2, uint16(math.MaxUint16)},
+		{uint32(math.MaxUint32), VT_UI4, uint32(math.MaxUint32)},
+		{uint64(math.MaxUint64), VT_UI8, uint64(math.MaxUint64)},
+		{uint(42), VT_UI4, uint32(42)},
+		{float32(-1.5), VT_R4, float32(-1.5)},
+		{math.MaxFloat64, VT_R8, math.MaxFloat64},
+		{Currency(math.MinInt64), VT_CY, Currency(math.MinInt64)},
+		{Date(-1.25), VT_DATE, Date(-1.25)},
+		{SCode(0x80020003), VT_ERROR, SCode(0x80020003)},
+		{decimal, VT_DECIMAL, decimal},
+		{"", VT_BSTR, ""},
+		{"héllo, wörld 🌍", VT_BSTR, "héllo, wörld 🌍"},
+		{[]int32{1, -2, 3}, VT_ARRAY | VT_I4, []int32{1, -2, 3}},
+		{[]int32{}, VT_ARRAY | VT_I4, []int32{}},
+		{[]byte("bytes"), VT_ARRAY | VT_UI1, []byte("bytes")},
+		{[]bool{true, false}, VT_ARRAY | VT_BOOL, []bool{true, false}},
+		{[]float64{0.5, -1}, VT_ARRAY | VT_R8, []float64{0.5, -1}},
+		{[]Currency{1, -1}, VT_ARRAY | VT_CY, []Currency{1, -1}},
+		{[]Date{0, -1.25}, VT_ARRAY | VT_DATE, []Date{0, -1.25}},
+		{[]SCode{0, 0x80020003}, VT_ARRAY | VT_ERROR, []SCode{0, 0x80020003}},
+		{[]Decimal{decimal, {}}, VT_ARRAY | VT_DECIMAL, []Decimal{decimal, {}}},
+		{[]string{"a", "", "c"}, VT_ARRAY | VT_BSTR, []string{"a", "", "c"}},
+		// Slices of types without a Variant Type are arrays of VARIANTs
+		{[]int{1, -2}, VT_ARRAY | VT_VARIANT, []any{int32(1), int32(-2)}},
+		{
+			[]any{int32(1), "two", nil, []string{"three"}, []any{uint8(4)}},
+			VT_ARRAY | VT_VARIANT,
+			[]any{int32(1), "two", nil, []string{"three"}, []any{uint8(4)}},
+		},
+	}
+	// int and uint only exceed 32 bits on 64-bit platforms
+	if large := int64(math.MaxUint32) + 1; int64(int(large)) == large {
+		tests = append(tests, variantCase{int(large), VT_I8, large}, variantCase{uint(large), VT_UI8, uint64(large)})
+	}
+	for _, tt := range tests {
+		v, err := ToVariant(tt.value)
+		if err != nil {
+			t.Errorf("ToVariant(%#v) returned an error: %s", tt.value, err)
+			continue
+		}
+		if v.VT != tt.vt {
+			t.Errorf("ToVariant(%#v) has the type 0x%x, want 0x%x", tt.value, v.VT, tt.vt)
+		}
+		got, err := FromVariant(&v)
+		if err != nil {
+			t.Errorf("FromVariant of %#v returned an error: %s", tt.value, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FromVariant of %#v = %#v, want %#v", tt.value, got, tt.want)
+		}
+		if err = v.Clear(); err != nil || v.VT != VT_EMPTY {
+			t.Errorf("Clear of %#v returned %v and left the type 0x%x", tt.value, err, v.VT)
+		}
+	}
+}
+
+func TestFromVariantTypes(t *testing.T) {
+	tests := []struct {
+		vt   uint16
+		bits uint64
+		want any
+	}{
+		{VT_NULL, 0, nil},
+		{VT_INT, math.MaxUint32, int32(-1)},
+		{VT_UINT, math.MaxUint32, uint32(math.MaxUint32)},
+		{VT_BOOL, 1, true},
+	}
+	for _, tt := range tests {
+		v := Variant{VT: tt.vt, Val: uintptr(tt.bits)}
+		if got, err := FromVariant(&v); err != nil || got != tt.want {
+			t.Errorf("FromVariant of the type 0x%x = %#v, %v, want %#v", tt.vt, got, err, tt.want)
+		}
+	}
+	if got, err := FromVariant(nil); got != nil || err != nil {
+		t.Errorf("FromVariant(nil) = %#v, %v", got, err)
+	}
+	for _, vt := range []uint16{0x0024, VT_VARIANT, VT_ARRAY | VT_VARIANT | VT_BYREF} {
+		v := Variant{VT: vt}
+		if got, err := FromVariant(&v); err == nil {
+			t.Errorf("FromVariant of the type 0x%x = %#v, want an error", vt, got)
+		}
+	}
+}
+
+func TestVariantInterfaces(t *testing.T) {
+	checkEmulatorAllocations(t)
+	obj, err := NewDispatchObject(&dispatchReceiver{})
+	if err != nil {
+		t.Fatalf("NewDispatchObject returned an error: %s", err)
+	}
+	defer obj.Release()
+	unknown := (*IUnknown)(unsafe.Pointer(obj))
+
+	tests := []variantCase{
+		{obj, VT_DISPATCH, unknown},
+		{unknown, VT_UNKNOWN, unknown},
+		{(*DispatchObject)(nil), VT_DISPATCH, (*IUnknown)(nil)},
+		{(*IUnknown)(nil), VT_UNKNOWN, (*IUnknown)(nil)},
+		{[]*DispatchObject{obj, nil}, VT_ARRAY | VT_DISPATCH, []*IUnknown{unknown, nil}},
+		{[]*IUnknown{unknown}, VT_ARRAY | VT_UNKNOWN, []*IUnknown{unknown}},
+		{[]any{obj, unknown}, VT_ARRAY | VT_VARIANT, []any{unknown, unknown}},
+	}
+	for _, tt := range tests {
+		v, err := ToVariant(tt.value)
+		if err != nil {
+			t.Errorf("ToVariant(%#v) returned an error: %s", tt.value, err)
+			continue
+		}
+		if v.VT != tt.vt {
+			t.Errorf("ToVariant(%#v) has the type 0x%x, want 0x%x", tt.value, v.VT, tt.vt)
+		}
+		if obj.RefCount() == 1 && tt.want != (*IUnknown)(nil) {
+			t.Errorf("the VARIANT of %#v holds no reference on the object", tt.value)
+		}
+		// FromVariant borrows the references of the VARIANT
+		refs := obj.RefCount()
+		got, err := FromVariant(&v)
+		if err != nil {
+			t.Errorf("FromVariant of %#v returned an error: %s", tt.value, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("FromVariant of %#v = %#v, want %#v", tt.value, got, tt.want)
+		}
+		if obj.RefCount() != refs {
+			t.Errorf("FromVariant of %#v changed the references of the object from %d to %d", tt.value, refs, obj.RefCount())
+		}
+		v.Clear()
+		if refs := obj.RefCount(); refs != 1 {
+			t.Errorf("the object has %d references once the VARIANT of %#v is cleared, want 1", refs, tt.value)
+		}
+	}
+}
+
+func TestVariantByRef(t *testing.T) {
+	i8, i32, u64, f64 := int8(-8), int32(-32), uint64(64), -6.4
+	cy, date, scode := Currency(15000), Date(-1.25), SCode(0x80020003)
+	inner, err := ToVariant(int32(42))
+	if err != nil {
+		t.Fatalf("ToVariant(int32) returned an error: %s", err)
+	}
+	tests := []struct {
+		ptr  any
+		vt   uint16
+		want any
+	}{
+		{&i8, VT_I1, i8},
+		{&i32, VT_I4, i32},
+		{&u64, VT_UI8, u64},
+		{&f64, VT_R8, f64},
+		{&cy, VT_CY, cy},
+		{&date, VT_DATE, date},
+		{&scode, VT_ERROR, scode},
+		{&inner, VT_VARIANT, int32(42)},
+	}
+	for _, tt := range tests {
+		v, err := ToVariant(tt.ptr)
+		if err != nil {
+			t.Errorf("ToVariant(%T) returned an error: %s", tt.ptr, err)
+			continue
+		}
+		if v.VT != tt.vt|VT_BYREF || v.ptr() != reflect.ValueOf(tt.ptr).UnsafePointer() {
+			t.Errorf("ToVariant(%T) has the type 0x%x and doesn't point to the value", tt.ptr, v.VT)
+		}
+		if got, err := FromVariant(&v); err != nil || got != tt.want {
+			t.Errorf("FromVariant of %T = %#v, %v, want %#v", tt.ptr, got, err, tt.want)
+		}
+	}
+
+	// The VARIANT points to the value rather than copying it
+	v, _ := ToVariant(&i32)
+	i32 = 320
+	if got, err := FromVariant(&v); err != nil || got != int32(320) {
+		t.Errorf("FromVariant of the updated value = %#v, %v, want 320", got, err)
+	}
+
+	v, err = ToVariant((*int32)(nil))
+	if err != nil {
+		t.Fatalf("ToVariant of a nil *int32 returned an error: %s", err)
+	}
+	if got, err := FromVariant(&v); err == nil {
+		t.Errorf("FromVariant of a VT_BYREF VARIANT holding NULL = %#v, want an error", got)
+	}
+}
+
+func TestToVariantUnsupported(t *testing.T) {
+	checkEmulatorAllocations(t)
+	var (
+		b       bool
+		s       string
+		d       Decimal
+		a       any
+		n       int
+		st      struct{}
+		unknown *IUnknown
+		obj     *DispatchObject
+	)
+	for _, value := range []any{
+		// Pointers to types that can't be passed by reference without a conversion the caller would not see
+		&b, &s, &d, &a, &unknown, &obj,
+		// Pointers to types without a Variant Type
+		&n, &st, &[]int32{},
+		map[string]int{}, make(chan int), func() {}, struct{}{}, complex(1, 1), uintptr(0),
+		// Slices holding unsupported elements are released once the conversion fails
+		[]any{"one", map[string]int{}},
+		[]*bool{&b},
+		[]any{"one", []any{"two", &s}},
+	} {
+		v, err := ToVariant(value)
+		if err == nil {
+			t.Errorf("ToVariant(%T) = a VARIANT of type 0x%x, want an error", value, v.VT)
+			v.Clear()
+		}
+	}
+}
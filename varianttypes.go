@@ -0,0 +1,247 @@
+package clr
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// oaDateEpoch is midnight, 30 December 1899, the OLE Automation date 0, in milliseconds since the Unix epoch
+	oaDateEpoch int64 = -2209161600000
+	// msPerDay is the number of milliseconds in a day
+	msPerDay int64 = 86400000
+	// minOADate and maxOADate are the exclusive bounds of the OLE Automation dates, from 1 January 100 to the end of
+	// 31 December 9999
+	minOADate float64 = -657435.0
+	maxOADate float64 = 2958466.0
+	// currencyScale is the number of fractional digits of a Currency
+	currencyScale = 4
+	// maxDecimalScale is the largest power of ten a Decimal can be scaled by
+	maxDecimalScale = 28
+)
+
+// NewDate converts the wall clock time of t, in its own location, to an OLE Automation date with millisecond
+// precision, the resolution of the managed DateTime.ToOADate method. Dates before 30 December 1899 are negative and,
+// as in OLE Automation, their fractional part is the time of day added to the negative day: 29 December 1899 06:00 is
+// -1.25. Dates outside of the years 100 to 9999 return an error
+// https://docs.microsoft.com/en-us/dotnet/api/system.datetime.tooadate?view=net-5.0
+func NewDate(t time.Time) (Date, error) {
+	_, offset := t.Zone()
+	millis := t.UnixMilli() + int64(offset)*1000 - oaDateEpoch
+	if millis < int64(minOADate+1)*msPerDay || millis >= int64(maxOADate)*msPerDay {
+		return 0, fmt.Errorf("%s is outside of the range of OLE Automation dates", t)
+	}
+	if millis < 0 {
+		// The time of day is counted forward from the negative day
+		if frac := millis % msPerDay; frac != 0 {
+			millis -= (msPerDay + frac) * 2
+		}
+	}
+	return Date(float64(millis) / float64(msPerDay)), nil
+}
+
+// Time converts the OLE Automation date to a time.Time in UTC, rounded to the nearest millisecond the same way as the
+// managed DateTime.FromOADate method. Values that are not between the years 100 and 9999 return an error
+// https://docs.microsoft.com/en-us/dotnet/api/system.datetime.fromoadate?view=net-5.0
+func (d Date) Time() (time.Time, error) {
+	v := float64(d)
+	// The comparisons are written so that NaN fails them
+	if !(v > minOADate && v < maxOADate) {
+		return time.Time{}, fmt.Errorf("%v is outside of the range of OLE Automation dates", v)
+	}
+	var millis int64
+	if v >= 0 {
+		millis = int64(v*float64(msPerDay) + 0.5)
+	} else {
+		millis = int64(v*float64(msPerDay) - 0.5)
+		// The fractional part of a negative date is a positive time of day
+		millis -= (millis % msPerDay) * 2
+	}
+	return time.UnixMilli(millis + oaDateEpoch).UTC(), nil
+}
+
+// String returns the date in the ISO 8601 format with milliseconds or, if it is out of range, the floating point value
+func (d Date) String() string {
+	t, err := d.Time()
+	if err != nil {
+		return fmt.Sprint(float64(d))
+	}
+	return t.Format("2006-01-02T15:04:05.000")
+}
+
+// NewCurrency parses a decimal number such as "-1234.5678" into a Currency. The number can not have more than 4
+// significant fractional digits or be outside of the range of a 64-bit integer scaled by 10,000
+func NewCurrency(s string) (Currency, error) {
+	m, scale, err := parseFixedPoint(s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a valid currency: %w", s, err)
+	}
+	return currencyFromFixedPoint(m, scale)
+}
+
+// CurrencyFromRat converts a rational number to a Currency. The conversion is exact, an error is returned if the
+// number has more than 4 fractional digits or does not fit
+func CurrencyFromRat(r *big.Rat) (Currency, error) {
+	m, scale, ok := ratToFixedPoint(r, currencyScale)
+	if !ok {
+		return 0, fmt.Errorf("%s can not be represented exactly as a currency", r.RatString())
+	}
+	return currencyFromFixedPoint(m, scale)
+}
+
+// currencyFromFixedPoint converts the number m / 10^scale to a Currency
+func currencyFromFixedPoint(m *big.Int, scale int) (Currency, error) {
+	if scale > currencyScale {
+		p := pow10(scale - currencyScale)
+		q, r := new(big.Int).QuoRem(m, p, new(big.Int))
+		if r.Sign() != 0 {
+			return 0, fmt.Errorf("a currency has at most %d fractional digits", currencyScale)
+		}
+		m = q
+	} else {
+		m = new(big.Int).Mul(m, pow10(currencyScale-scale))
+	}
+	if !m.IsInt64() {
+		return 0, fmt.Errorf("%s is outside of the range of a currency", formatFixedPoint(m, currencyScale))
+	}
+	return Currency(m.Int64()), nil
+}
+
+// Rat returns the exact value of the Currency
+func (c Currency) Rat() *big.Rat {
+	return big.NewRat(int64(c), 10000)
+}
+
+// String returns the Currency as a decimal number without trailing fractional zeros, such as "-12.5"
+func (c Currency) String() string {
+	s := formatFixedPoint(big.NewInt(int64(c)), currencyScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// NewDecimal parses a decimal number such as "-79228162514264337593543950335" or "0.0000000000000000000000000001"
+// into a Decimal. The number of fractional digits, including trailing zeros, becomes the scale as in the managed
+// decimal type. The 96-bit integer is never rounded, an error is returned if the number does not fit
+func NewDecimal(s string) (Decimal, error) {
+	m, scale, err := parseFixedPoint(s)
+	if err != nil {
+		return Decimal{}, fmt.Errorf("%q is not a valid decimal: %w", s, err)
+	}
+	// Trailing zeros past the largest scale don't change the value
+	for scale > maxDecimalScale && m.Sign() != 0 {
+		q, r := new(big.Int).QuoRem(m, big.NewInt(10), new(big.Int))
+		if r.Sign() != 0 {
+			break
+		}
+		m, scale = q, scale-1
+	}
+	if m.Sign() == 0 && scale > maxDecimalScale {
+		scale = maxDecimalScale
+	}
+	return decimalFromFixedPoint(m, scale)
+}
+
+// DecimalFromRat converts a rational number to a Decimal with the smallest scale that represents it exactly. An error
+// is returned if the number needs more than 28 fractional digits or does not fit in 96 bits
+func DecimalFromRat(r *big.Rat) (Decimal, error) {
+	m, scale, ok := ratToFixedPoint(r, maxDecimalScale)
+	if !ok {
+		return Decimal{}, fmt.Errorf("%s can not be represented exactly as a decimal", r.RatString())
+	}
+	return decimalFromFixedPoint(m, scale)
+}
+
+// decimalFromFixedPoint converts the number m / 10^scale to a Decimal
+func decimalFromFixedPoint(m *big.Int, scale int) (Decimal, error) {
+	if scale > maxDecimalScale {
+		return Decimal{}, fmt.Errorf("a decimal has at most %d fractional digits", maxDecimalScale)
+	}
+	var d Decimal
+	d.Scale = byte(scale)
+	if m.Sign() < 0 {
+		d.Sign = DECIMAL_NEG
+	}
+	abs := new(big.Int).Abs(m)
+	if abs.BitLen() > 96 {
+		return Decimal{}, fmt.Errorf("%s is outside of the range of a decimal", formatFixedPoint(m, scale))
+	}
+	d.Lo64 = new(big.Int).And(abs, new(big.Int).SetUint64(1<<64-1)).Uint64()
+	d.Hi32 = uint32(new(big.Int).Rsh(abs, 64).Uint64())
+	return d, nil
+}
+
+// mantissa returns the signed 96-bit integer of the Decimal
+func (d Decimal) mantissa() *big.Int {
+	m := new(big.Int).SetUint64(uint64(d.Hi32))
+	m.Lsh(m, 64)
+	m.Or(m, new(big.Int).SetUint64(d.Lo64))
+	if d.Sign&DECIMAL_NEG != 0 {
+		m.Neg(m)
+	}
+	return m
+}
+
+// Rat returns the exact value of the Decimal
+func (d Decimal) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(d.mantissa(), pow10(int(d.Scale)))
+}
+
+// String returns the Decimal as a decimal number with as many fractional digits as its scale, such as "1.50"
+func (d Decimal) String() string {
+	return formatFixedPoint(d.mantissa(), int(d.Scale))
+}
+
+// parseFixedPoint parses an optionally signed decimal number without an exponent and returns it as m / 10^scale
+func parseFixedPoint(s string) (m *big.Int, scale int, err error) {
+	digits := strings.TrimLeft(s, "+-")
+	if len(s)-len(digits) > 1 {
+		return nil, 0, fmt.Errorf("more than one sign")
+	}
+	integer, fraction, _ := strings.Cut(digits, ".")
+	if integer == "" && fraction == "" {
+		return nil, 0, fmt.Errorf("no digits")
+	}
+	for _, c := range integer + fraction {
+		if c < '0' || c > '9' {
+			return nil, 0, fmt.Errorf("invalid character %q", c)
+		}
+	}
+	m, _ = new(big.Int).SetString("0"+integer+fraction, 10)
+	if strings.HasPrefix(s, "-") {
+		m.Neg(m)
+	}
+	return m, len(fraction), nil
+}
+
+// ratToFixedPoint returns r as m / 10^scale with the smallest scale up to maxScale, ok is false if there is none
+func ratToFixedPoint(r *big.Rat, maxScale int) (m *big.Int, scale int, ok bool) {
+	for scale = 0; scale <= maxScale; scale++ {
+		x := new(big.Rat).Mul(r, new(big.Rat).SetInt(pow10(scale)))
+		if x.IsInt() {
+			return x.Num(), scale, true
+		}
+	}
+	return nil, 0, false
+}
+
+// formatFixedPoint formats the number m / 10^scale with exactly scale fractional digits
+func formatFixedPoint(m *big.Int, scale int) string {
+	digits := new(big.Int).Abs(m).String()
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if m.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// pow10 returns 10^n
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
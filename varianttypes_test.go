@@ -0,0 +1,299 @@
+package clr
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestNewDate(t *testing.T) {
+	tests := []struct {
+		name string
+		time time.Time
+		want Date
+	}{
+		{"epoch", time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC), 0},
+		{"epoch noon", time.Date(1899, 12, 30, 12, 0, 0, 0, time.UTC), 0.5},
+		{"1900", time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC), 2},
+		{"1900 evening", time.Date(1900, 1, 1, 18, 0, 0, 0, time.UTC), 2.75},
+		{"day before the epoch", time.Date(1899, 12, 29, 0, 0, 0, 0, time.UTC), -1},
+		{"day before the epoch morning", time.Date(1899, 12, 29, 6, 0, 0, 0, time.UTC), -1.25},
+		{"day before the epoch evening", time.Date(1899, 12, 29, 18, 0, 0, 0, time.UTC), -1.75},
+		{"1800", time.Date(1800, 1, 1, 0, 0, 0, 0, time.UTC), -36522},
+		{"1800 evening", time.Date(1800, 1, 1, 18, 0, 0, 0, time.UTC), -36522.75},
+		{"wall clock of the location", time.Date(1900, 1, 1, 18, 0, 0, 0, time.FixedZone("UTC+2", 2*3600)), 2.75},
+		{"first day", time.Date(100, 1, 1, 0, 0, 0, 0, time.UTC), -657434},
+		{"last millisecond", time.Date(9999, 12, 31, 23, 59, 59, 999e6, time.UTC), Date(float64(2958466*msPerDay-1) / float64(msPerDay))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDate(tt.time)
+			if err != nil {
+				t.Fatalf("NewDate returned an error: %s", err)
+			}
+			if got != tt.want {
+				t.Errorf("NewDate(%s) = %v, want %v", tt.time, float64(got), float64(tt.want))
+			}
+		})
+	}
+}
+
+func TestNewDateOutOfRange(t *testing.T) {
+	for _, tm := range []time.Time{
+		time.Date(99, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if d, err := NewDate(tm); err == nil {
+			t.Errorf("NewDate(%s) = %v, want an error", tm, float64(d))
+		}
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	tests := []struct {
+		date Date
+		want time.Time
+	}{
+		{0, time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{2.75, time.Date(1900, 1, 1, 18, 0, 0, 0, time.UTC)},
+		// The time of day of a negative date is counted forward from its day, -0.5 is noon of day 0
+		{-0.5, time.Date(1899, 12, 30, 12, 0, 0, 0, time.UTC)},
+		{-1, time.Date(1899, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{-1.25, time.Date(1899, 12, 29, 6, 0, 0, 0, time.UTC)},
+		{-1.75, time.Date(1899, 12, 29, 18, 0, 0, 0, time.UTC)},
+		{-36522.75, time.Date(1800, 1, 1, 18, 0, 0, 0, time.UTC)},
+		// Rounded to the nearest millisecond
+		{Date(1.4 / float64(msPerDay)), time.Date(1899, 12, 30, 0, 0, 0, 1e6, time.UTC)},
+		{Date(0.4 / float64(msPerDay)), time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := tt.date.Time()
+		if err != nil {
+			t.Errorf("Date(%v).Time returned an error: %s", float64(tt.date), err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("Date(%v).Time() = %s, want %s", float64(tt.date), got, tt.want)
+		}
+	}
+}
+
+func TestDateTimeOutOfRange(t *testing.T) {
+	for _, d := range []Date{Date(minOADate), Date(maxOADate), Date(math.NaN()), Date(math.Inf(-1))} {
+		if _, err := d.Time(); err == nil {
+			t.Errorf("Date(%v).Time did not return an error", float64(d))
+		}
+		if got, want := d.String(), fmt.Sprint(float64(d)); got != want {
+			t.Errorf("Date(%v).String() = %q, want %q", float64(d), got, want)
+		}
+	}
+}
+
+func TestDateRoundTrip(t *testing.T) {
+	for _, want := range []time.Time{
+		time.Date(2024, 2, 29, 12, 34, 56, 789e6, time.UTC),
+		time.Date(1899, 12, 29, 23, 59, 59, 999e6, time.UTC),
+		time.Date(1666, 9, 2, 1, 2, 3, 4e6, time.UTC),
+		time.Date(100, 1, 1, 0, 0, 0, 1e6, time.UTC),
+	} {
+		d, err := NewDate(want)
+		if err != nil {
+			t.Errorf("NewDate(%s) returned an error: %s", want, err)
+			continue
+		}
+		got, err := d.Time()
+		if err != nil {
+			t.Errorf("Date(%v).Time returned an error: %s", float64(d), err)
+			continue
+		}
+		if !got.Equal(want) {
+			t.Errorf("the round trip of %s through %v returned %s", want, float64(d), got)
+		}
+		if s := d.String(); s != want.Format("2006-01-02T15:04:05.000") {
+			t.Errorf("Date(%v).String() = %q", float64(d), s)
+		}
+	}
+}
+
+func TestNewCurrency(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   Currency
+		string string
+	}{
+		{"0", 0, "0"},
+		{"12.5", 125000, "12.5"},
+		{"-0.0001", -1, "-0.0001"},
+		{"+100", 1000000, "100"},
+		{".5", 5000, "0.5"},
+		{"1.23450", 12345, "1.2345"},
+		{"922337203685477.5807", math.MaxInt64, "922337203685477.5807"},
+		{"-922337203685477.5808", math.MinInt64, "-922337203685477.5808"},
+	}
+	for _, tt := range tests {
+		got, err := NewCurrency(tt.in)
+		if err != nil {
+			t.Errorf("NewCurrency(%q) returned an error: %s", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NewCurrency(%q) = %d, want %d", tt.in, int64(got), int64(tt.want))
+		}
+		if s := got.String(); s != tt.string {
+			t.Errorf("Currency(%d).String() = %q, want %q", int64(got), s, tt.string)
+		}
+		if r := got.Rat(); r.Cmp(big.NewRat(int64(tt.want), 10000)) != 0 {
+			t.Errorf("Currency(%d).Rat() = %s", int64(got), r.RatString())
+		}
+	}
+}
+
+func TestNewCurrencyInvalid(t *testing.T) {
+	for _, in := range []string{
+		"", ".", "--1", "1-2", "1e3", "1,5", "1.23456",
+		"922337203685477.5808", "-922337203685477.5809", "10000000000000000",
+	} {
+		if got, err := NewCurrency(in); err == nil {
+			t.Errorf("NewCurrency(%q) = %d, want an error", in, int64(got))
+		}
+	}
+}
+
+func TestCurrencyFromRat(t *testing.T) {
+	maxCurrency := new(big.Rat).SetFrac(big.NewInt(math.MaxInt64), big.NewInt(10000))
+	if got, err := CurrencyFromRat(maxCurrency); err != nil || got != math.MaxInt64 {
+		t.Errorf("CurrencyFromRat(%s) = %d, %v", maxCurrency.RatString(), int64(got), err)
+	}
+	for _, r := range []*big.Rat{
+		big.NewRat(1, 3),
+		big.NewRat(1, 100000),
+		new(big.Rat).Add(maxCurrency, big.NewRat(1, 10000)),
+	} {
+		if got, err := CurrencyFromRat(r); err == nil {
+			t.Errorf("CurrencyFromRat(%s) = %d, want an error", r.RatString(), int64(got))
+		}
+	}
+}
+
+func TestNewDecimal(t *testing.T) {
+	tests := []struct {
+		in    string
+		scale byte
+		sign  byte
+		hi32  uint32
+		lo64  uint64
+		str   string
+	}{
+		{"0", 0, 0, 0, 0, "0"},
+		{"-0.0", 1, 0, 0, 0, "0.0"},
+		{"1.50", 2, 0, 0, 150, "1.50"},
+		{"-12.345", 3, DECIMAL_NEG, 0, 12345, "-12.345"},
+		{"18446744073709551616", 0, 0, 1, 0, "18446744073709551616"},
+		{"79228162514264337593543950335", 0, 0, math.MaxUint32, math.MaxUint64, "79228162514264337593543950335"},
+		{"-79228162514264337593543950335", 0, DECIMAL_NEG, math.MaxUint32, math.MaxUint64, "-79228162514264337593543950335"},
+		{"0.0000000000000000000000000001", 28, 0, 0, 1, "0.0000000000000000000000000001"},
+		// Trailing zeros past the largest scale are dropped
+		{"0.00000000000000000000000000010", 28, 0, 0, 1, "0.0000000000000000000000000001"},
+		{"0.000000000000000000000000000000", 28, 0, 0, 0, "0.0000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		got, err := NewDecimal(tt.in)
+		if err != nil {
+			t.Errorf("NewDecimal(%q) returned an error: %s", tt.in, err)
+			continue
+		}
+		if got.Scale != tt.scale || got.Sign != tt.sign || got.Hi32 != tt.hi32 || got.Lo64 != tt.lo64 {
+			t.Errorf("NewDecimal(%q) = %+v", tt.in, got)
+		}
+		if s := got.String(); s != tt.str {
+			t.Errorf("NewDecimal(%q).String() = %q, want %q", tt.in, s, tt.str)
+		}
+		want, _ := new(big.Rat).SetString(tt.in)
+		if r := got.Rat(); r.Cmp(want) != 0 {
+			t.Errorf("NewDecimal(%q).Rat() = %s", tt.in, r.RatString())
+		}
+	}
+}
+
+func TestNewDecimalInvalid(t *testing.T) {
+	for _, in := range []string{
+		"", "+-1", "1.2.3", "0x10",
+		"79228162514264337593543950336",
+		"-79228162514264337593543950336",
+		"0.00000000000000000000000000001",
+		"7922816251426433759354395033.55",
+	} {
+		if got, err := NewDecimal(in); err == nil {
+			t.Errorf("NewDecimal(%q) = %+v, want an error", in, got)
+		}
+	}
+}
+
+func TestDecimalFromRat(t *testing.T) {
+	tests := []struct {
+		r     *big.Rat
+		scale byte
+		str   string
+	}{
+		{big.NewRat(1, 8), 3, "0.125"},
+		{big.NewRat(-5, 2), 1, "-2.5"},
+		{big.NewRat(100, 1), 0, "100"},
+	}
+	for _, tt := range tests {
+		got, err := DecimalFromRat(tt.r)
+		if err != nil {
+			t.Errorf("DecimalFromRat(%s) returned an error: %s", tt.r.RatString(), err)
+			continue
+		}
+		if got.Scale != tt.scale || got.String() != tt.str {
+			t.Errorf("DecimalFromRat(%s) = %s with scale %d", tt.r.RatString(), got, got.Scale)
+		}
+	}
+	for _, r := range []*big.Rat{big.NewRat(1, 3), new(big.Rat).SetInt(new(big.Int).Lsh(big.NewInt(1), 96))} {
+		if got, err := DecimalFromRat(r); err == nil {
+			t.Errorf("DecimalFromRat(%s) = %s, want an error", r.RatString(), got)
+		}
+	}
+}
+
+func TestVariantTimeAndRat(t *testing.T) {
+	tm := time.Date(1899, 12, 29, 6, 0, 0, 0, time.UTC)
+	v, err := ToVariant(tm)
+	if err != nil {
+		t.Fatalf("ToVariant(%s) returned an error: %s", tm, err)
+	}
+	if v.VT != VT_DATE {
+		t.Errorf("ToVariant(time.Time) has the type 0x%x, want VT_DATE", v.VT)
+	}
+	if got, err := FromVariant(&v); err != nil || got != Date(-1.25) {
+		t.Errorf("FromVariant of the time.Time VARIANT = %v, %v", got, err)
+	}
+	if _, err := ToVariant(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)); err == nil {
+		t.Errorf("ToVariant accepted a time.Time outside of the range of OLE Automation dates")
+	}
+
+	r := big.NewRat(-1234567, 1000)
+	v, err = ToVariant(r)
+	if err != nil {
+		t.Fatalf("ToVariant(%s) returned an error: %s", r.RatString(), err)
+	}
+	if v.VT != VT_DECIMAL {
+		t.Errorf("ToVariant(*big.Rat) has the type 0x%x, want VT_DECIMAL", v.VT)
+	}
+	got, err := FromVariant(&v)
+	if err != nil {
+		t.Fatalf("FromVariant of the *big.Rat VARIANT returned an error: %s", err)
+	}
+	d, ok := got.(Decimal)
+	if !ok || d.Scale != 3 || d.Sign != DECIMAL_NEG || d.Rat().Cmp(r) != 0 {
+		t.Errorf("FromVariant of the *big.Rat VARIANT = %#v", got)
+	}
+	if _, err := ToVariant(big.NewRat(1, 3)); err == nil {
+		t.Errorf("ToVariant accepted a *big.Rat that is not a decimal")
+	}
+	if v, err := ToVariant((*big.Rat)(nil)); err != nil || v.VT != VT_EMPTY {
+		t.Errorf("ToVariant of a nil *big.Rat = 0x%x, %v", v.VT, err)
+	}
+}